@@ -0,0 +1,293 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// binaryVersion is the version of the binary encoding,
+// written as the first byte.
+const binaryVersion = 1
+
+// Flags describing the optional sections of the binary
+// encoding, written as the second byte. The identity section
+// holds the ID and creation time, it's always written but
+// may be absent from data encoded by earlier releases.
+const (
+	binaryFlagFrames byte = 1 << iota
+	binaryFlagIdentity
+	binaryFlagsAll = binaryFlagFrames | binaryFlagIdentity
+)
+
+// maxBinaryDepth is the maximum amount of nested errors
+// decoded, to guard against malicious input.
+const maxBinaryDepth = 100
+
+// Tags describing the wrapped error in the binary encoding.
+const (
+	binaryErrNone byte = iota
+	binaryErrString
+	binaryErrNested
+)
+
+// ErrInvalidBinary is returned by UnmarshalBinary when the
+// data is not a valid binary encoding of an Error.
+var ErrInvalidBinary = errors.New("errors: invalid binary encoding")
+
+// BinaryOptions defines the optional sections included by
+// MarshalBinaryWith.
+type BinaryOptions struct {
+	// Includes the stacktrace frames of every Error within
+	// the chain, which typically make up the bulk of the
+	// encoding.
+	Frames bool
+}
+
+// MarshalBinary implements encoding.BinaryMarshaler using a
+// compact, versioned format. The code, message, operation,
+// file line, ID, creation time and metadata are encoded,
+// along with the wrapped chain. Wrapped errors that are not of type Error
+// are encoded as their string. Stacktrace frames are
+// omitted, use MarshalBinaryWith to include them.
+func (e *Error) MarshalBinary() ([]byte, error) {
+	return e.MarshalBinaryWith(BinaryOptions{})
+}
+
+// MarshalBinaryWith encodes the error in the same format as
+// MarshalBinary, including the optional sections enabled
+// by opts.
+func (e *Error) MarshalBinaryWith(opts BinaryOptions) ([]byte, error) {
+	flags := binaryFlagIdentity
+	if opts.Frames {
+		flags |= binaryFlagFrames
+	}
+	return e.appendBinary([]byte{binaryVersion, flags}, flags), nil
+}
+
+// UnmarshalBinary implements encoding.BinaryUnmarshaler,
+// decoding data produced by MarshalBinary or
+// MarshalBinaryWith. Decoded errors retain their stacktrace
+// frames for reporting if they were encoded, but not their
+// program counters.
+func (e *Error) UnmarshalBinary(data []byte) error {
+	if len(data) == 0 {
+		return fmt.Errorf("%w: empty input", ErrInvalidBinary)
+	}
+	if data[0] != binaryVersion {
+		return fmt.Errorf("%w: unsupported version %d", ErrInvalidBinary, data[0])
+	}
+	if len(data) < 2 {
+		return fmt.Errorf("%w: missing flags", ErrInvalidBinary)
+	}
+	if data[1]&^binaryFlagsAll != 0 {
+		return fmt.Errorf("%w: unknown flags %#x", ErrInvalidBinary, data[1])
+	}
+	d := binaryDecoder{buf: data[2:], flags: data[1]}
+	var out Error
+	d.decode(&out, 0)
+	if d.err != nil {
+		return d.err
+	}
+	if len(d.buf) != 0 {
+		return fmt.Errorf("%w: %d trailing bytes", ErrInvalidBinary, len(d.buf))
+	}
+	*e = out
+	return nil
+}
+
+// appendBinary appends the encoding of the error, without
+// the header, to b.
+func (e *Error) appendBinary(b []byte, flags byte) []byte {
+	b = appendString(b, string(e.Code))
+	b = appendString(b, e.Message)
+	b = appendString(b, e.Operation)
+	b = appendString(b, e.FileLine())
+
+	if flags&binaryFlagIdentity != 0 {
+		b = appendString(b, e.id)
+		var nanos int64
+		if !e.time.IsZero() {
+			nanos = e.time.UnixNano()
+		}
+		b = binary.AppendVarint(b, nanos)
+	}
+
+	keys := make([]string, 0, len(e.Metadata))
+	for k := range e.Metadata {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	b = binary.AppendUvarint(b, uint64(len(keys)))
+	for _, k := range keys {
+		b = appendString(b, k)
+		b = appendString(b, e.Metadata[k])
+	}
+
+	if flags&binaryFlagFrames != 0 {
+		frames := e.Frames()
+		b = binary.AppendUvarint(b, uint64(len(frames)))
+		for _, f := range frames {
+			b = appendString(b, f.Function)
+			b = appendString(b, f.File)
+			b = binary.AppendUvarint(b, uint64(f.Line))
+		}
+	}
+
+	wrapped, ok := e.Err.(*Error)
+	switch {
+	case e.Err == nil:
+		b = append(b, binaryErrNone)
+	case ok && wrapped != nil:
+		b = append(b, binaryErrNested)
+		b = wrapped.appendBinary(b, flags)
+	default:
+		b = append(b, binaryErrString)
+		b = appendString(b, e.Err.Error())
+	}
+	return b
+}
+
+// appendString appends the length prefixed string to b.
+func appendString(b []byte, s string) []byte {
+	b = binary.AppendUvarint(b, uint64(len(s)))
+	return append(b, s...)
+}
+
+// binaryDecoder reads the binary encoding, recording the
+// first error encountered so reads can be chained.
+type binaryDecoder struct {
+	buf   []byte
+	flags byte
+	err   error
+}
+
+// decode reads an error into e.
+func (d *binaryDecoder) decode(e *Error, depth int) {
+	if depth > maxBinaryDepth {
+		d.fail("maximum depth exceeded")
+		return
+	}
+	e.Code = ErrorCode(d.string())
+	e.Message = d.string()
+	e.Operation = d.string()
+	e.fileLine = d.string()
+
+	if d.flags&binaryFlagIdentity != 0 {
+		e.id = d.string()
+		if nanos := d.varint(); nanos != 0 {
+			e.time = time.Unix(0, nanos).UTC()
+		}
+	}
+
+	if n := d.length(2); n > 0 {
+		e.Metadata = make(map[string]string, n)
+		for i := 0; i < n; i++ {
+			k := d.string()
+			e.Metadata[k] = d.string()
+		}
+	}
+
+	if d.flags&binaryFlagFrames != 0 {
+		if n := d.length(3); n > 0 {
+			e.stack = make([]Frame, n)
+			for i := range e.stack {
+				e.stack[i] = Frame{Function: d.string(), File: d.string(), Line: int(d.uvarint())}
+			}
+		}
+	}
+
+	switch d.byte() {
+	case binaryErrNone:
+	case binaryErrString:
+		e.Err = errors.New(d.string())
+	case binaryErrNested:
+		wrapped := &Error{}
+		d.decode(wrapped, depth+1)
+		e.Err = wrapped
+	default:
+		d.fail("unknown wrapped error tag")
+	}
+}
+
+// fail records the error if none has been recorded.
+func (d *binaryDecoder) fail(msg string) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: %s", ErrInvalidBinary, msg)
+	}
+	d.buf = nil
+}
+
+// byte reads a single byte.
+func (d *binaryDecoder) byte() byte {
+	if d.err != nil {
+		return 0
+	}
+	if len(d.buf) == 0 {
+		d.fail("unexpected end of input")
+		return 0
+	}
+	b := d.buf[0]
+	d.buf = d.buf[1:]
+	return b
+}
+
+// uvarint reads a variable length integer.
+func (d *binaryDecoder) uvarint() uint64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Uvarint(d.buf)
+	if n <= 0 {
+		d.fail("malformed integer")
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+// varint reads a variable length signed integer.
+func (d *binaryDecoder) varint() int64 {
+	if d.err != nil {
+		return 0
+	}
+	v, n := binary.Varint(d.buf)
+	if n <= 0 {
+		d.fail("malformed integer")
+		return 0
+	}
+	d.buf = d.buf[n:]
+	return v
+}
+
+// length reads the amount of items that follow, each of
+// which occupies at least minSize bytes, rejecting lengths
+// that cannot fit within the remaining input.
+func (d *binaryDecoder) length(minSize int) int {
+	n := d.uvarint()
+	if d.err != nil {
+		return 0
+	}
+	if n > uint64(len(d.buf)/minSize) {
+		d.fail("length exceeds input")
+		return 0
+	}
+	return int(n)
+}
+
+// string reads a length prefixed string.
+func (d *binaryDecoder) string() string {
+	n := d.length(1)
+	if d.err != nil {
+		return ""
+	}
+	s := string(d.buf[:n])
+	d.buf = d.buf[n:]
+	return s
+}
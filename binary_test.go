@@ -0,0 +1,281 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestError_MarshalBinary(t *testing.T) {
+	tt := map[string]struct {
+		input *Error
+	}{
+		"Empty": {
+			&Error{},
+		},
+		"Fields": {
+			&Error{Code: NOTFOUND, Message: "message", Operation: "op", fileLine: "file.go:10"},
+		},
+		"Metadata": {
+			&Error{Code: INVALID, Metadata: map[string]string{"a": "1", "b": "2"}},
+		},
+		"Standard Error": {
+			&Error{Code: INTERNAL, Err: errors.New("error")},
+		},
+		"Nested": {
+			&Error{Code: INTERNAL, Operation: "outer", Err: &Error{Code: NOTFOUND, Err: errors.New("error")}},
+		},
+		"Frames": {
+			&Error{Code: INTERNAL, stack: []Frame{{Function: "main.main", File: "main.go", Line: 5}}},
+		},
+		"Identity": {
+			&Error{Code: INTERNAL, id: "01ARZ3NDEKTSV4RRFFQ69G5FAV", time: time.Date(2022, 1, 1, 12, 0, 0, 1, time.UTC)},
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			buf, err := test.input.MarshalBinaryWith(BinaryOptions{Frames: true})
+			if err != nil {
+				t.Fatalf("expecting nil, got %s", err)
+			}
+			got := &Error{}
+			if err = got.UnmarshalBinary(buf); err != nil {
+				t.Fatalf("expecting nil, got %s", err)
+			}
+			if !reflect.DeepEqual(test.input.Error(), got.Error()) {
+				t.Fatalf("expecting %s, got %s", test.input.Error(), got.Error())
+			}
+			if !reflect.DeepEqual(test.input.Metadata, got.Metadata) {
+				t.Fatalf("expecting %v, got %v", test.input.Metadata, got.Metadata)
+			}
+			if !reflect.DeepEqual(test.input.stack, got.stack) {
+				t.Fatalf("expecting %v, got %v", test.input.stack, got.stack)
+			}
+			if test.input.id != got.id || !test.input.time.Equal(got.time) {
+				t.Fatalf("expecting %s at %s, got %s at %s", test.input.id, test.input.time, got.id, got.time)
+			}
+		})
+	}
+}
+
+func TestError_MarshalBinary_Stack(t *testing.T) {
+	e := NewInternal(NewNotFound(errors.New("error"), "message", "inner"), "message", "outer")
+	buf, err := e.MarshalBinaryWith(BinaryOptions{Frames: true})
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	got := &Error{}
+	if err = got.UnmarshalBinary(buf); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	if !reflect.DeepEqual(e.Frames(), got.Frames()) {
+		t.Fatalf("expecting %v, got %v", e.Frames(), got.Frames())
+	}
+	if !reflect.DeepEqual(e.FileLine(), got.FileLine()) {
+		t.Fatalf("expecting %s, got %s", e.FileLine(), got.FileLine())
+	}
+	var inner *Error
+	if !errors.As(got.Err, &inner) || inner.Operation != "inner" {
+		t.Fatalf("expecting wrapped Error, got %v", got.Err)
+	}
+}
+
+func TestError_MarshalBinary_Frames(t *testing.T) {
+	e := NewInternal(errors.New("error"), "message", "op")
+	buf, err := e.MarshalBinary()
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	got := &Error{}
+	if err = got.UnmarshalBinary(buf); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	if got.Frames() != nil {
+		t.Fatalf("expecting no frames, got %v", got.Frames())
+	}
+	if !reflect.DeepEqual(e.FileLine(), got.FileLine()) {
+		t.Fatalf("expecting %s, got %s", e.FileLine(), got.FileLine())
+	}
+}
+
+func TestError_MarshalBinary_Identity(t *testing.T) {
+	withConfig(t, func(cfg *Config) { cfg.GenerateIDs = true })
+
+	e := NewInternal(NewNotFound(nil, "message", "inner"), "message", "outer")
+	buf, err := e.MarshalBinary()
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	got := &Error{}
+	if err = got.UnmarshalBinary(buf); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	if ID(got) == "" || ID(got) != ID(e) {
+		t.Fatalf("expecting %s, got %s", ID(e), ID(got))
+	}
+	if !e.Time().Equal(got.Time()) {
+		t.Fatalf("expecting %s, got %s", e.Time(), got.Time())
+	}
+	inner, ok := got.Err.(*Error)
+	if !ok || inner.Time().IsZero() {
+		t.Fatalf("expecting wrapped Error with a creation time, got %v", got.Err)
+	}
+}
+
+func TestError_UnmarshalBinary_NoIdentity(t *testing.T) {
+	e := &Error{Code: NOTFOUND, Message: "message", id: "id", time: time.Now()}
+	got := &Error{}
+	if err := got.UnmarshalBinary(e.appendBinary([]byte{binaryVersion, 0}, 0)); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	if got.id != "" || !got.time.IsZero() {
+		t.Fatalf("expecting no identity, got %s at %s", got.id, got.time)
+	}
+	if !reflect.DeepEqual(e.Error(), got.Error()) {
+		t.Fatalf("expecting %s, got %s", e.Error(), got.Error())
+	}
+}
+
+func TestError_MarshalBinary_Size(t *testing.T) {
+	e := NewNotFound(errors.New("sql: no rows"), "User not found", "UserStore.Find").WithMeta("id", "1")
+	if len(e.Frames()) == 0 {
+		t.Fatalf("expecting a captured stack")
+	}
+	bin, _ := e.MarshalBinary()
+	withFrames, _ := e.MarshalBinaryWith(BinaryOptions{Frames: true})
+	js, _ := json.Marshal(e)
+	if len(bin) >= len(js)/2 {
+		t.Fatalf("expecting binary (%d bytes) to be less than half of json (%d bytes)", len(bin), len(js))
+	}
+	if len(withFrames) <= len(bin) {
+		t.Fatalf("expecting frames (%d bytes) to be larger than without (%d bytes)", len(withFrames), len(bin))
+	}
+}
+
+func TestError_UnmarshalBinary(t *testing.T) {
+	valid, _ := (&Error{Code: NOTFOUND, Message: "message"}).MarshalBinary()
+
+	tt := map[string]struct {
+		input []byte
+		want  string
+	}{
+		"Empty": {
+			nil,
+			"empty input",
+		},
+		"Version": {
+			[]byte{2},
+			"unsupported version 2",
+		},
+		"Truncated": {
+			valid[:len(valid)-1],
+			"unexpected end of input",
+		},
+		"Trailing": {
+			append(append([]byte{}, valid...), 0),
+			"1 trailing bytes",
+		},
+		"Flags Missing": {
+			[]byte{binaryVersion},
+			"missing flags",
+		},
+		"Flags Unknown": {
+			[]byte{binaryVersion, 0x80},
+			"unknown flags 0x80",
+		},
+		"Length": {
+			[]byte{binaryVersion, 0, 0xff, 0x01},
+			"length exceeds input",
+		},
+		"Tag": {
+			append(append([]byte{}, valid[:len(valid)-1]...), 9),
+			"unknown wrapped error tag",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := &Error{}
+			err := got.UnmarshalBinary(test.input)
+			if !errors.Is(err, ErrInvalidBinary) {
+				t.Fatalf("expecting %s, got %v", ErrInvalidBinary, err)
+			}
+			if !reflect.DeepEqual(ErrInvalidBinary.Error()+": "+test.want, err.Error()) {
+				t.Fatalf("expecting %s, got %s", test.want, err)
+			}
+		})
+	}
+}
+
+func FuzzError_MarshalBinary(f *testing.F) {
+	f.Add("not_found", "message", "op", "file.go:1", "key", "value", "error", "id", int64(1), 3)
+	f.Add("", "", "", "", "", "", "", "", int64(0), 0)
+	f.Fuzz(func(t *testing.T, code, message, op, fileLine, key, value, wrapped, id string, nanos int64, line int) {
+		e := &Error{
+			Code:      ErrorCode(code),
+			Message:   message,
+			Operation: op,
+			fileLine:  fileLine,
+			Metadata:  map[string]string{key: value},
+			stack:     []Frame{{Function: op, File: fileLine, Line: line}},
+			id:        id,
+		}
+		if nanos != 0 {
+			e.time = time.Unix(0, nanos).UTC()
+		}
+		if wrapped != "" {
+			e.Err = &Error{Message: wrapped, Err: errors.New(wrapped)}
+		}
+		buf, err := e.MarshalBinaryWith(BinaryOptions{Frames: true})
+		if err != nil {
+			t.Fatalf("expecting nil, got %s", err)
+		}
+		got := &Error{}
+		if err = got.UnmarshalBinary(buf); err != nil {
+			t.Fatalf("expecting nil, got %s", err)
+		}
+		again, _ := got.MarshalBinaryWith(BinaryOptions{Frames: true})
+		if !reflect.DeepEqual(buf, again) {
+			t.Fatalf("expecting round trip to be stable")
+		}
+		if !reflect.DeepEqual(e.Error(), got.Error()) || !reflect.DeepEqual(e.Metadata, got.Metadata) ||
+			!reflect.DeepEqual(e.stack, got.stack) || e.id != got.id || !reflect.DeepEqual(e.time, got.time) {
+			t.Fatalf("expecting %+v, got %+v", e, got)
+		}
+	})
+}
+
+func FuzzError_UnmarshalBinary(f *testing.F) {
+	seed, _ := (&Error{Code: NOTFOUND, Message: "message", Err: &Error{Err: errors.New("error")}}).MarshalBinary()
+	f.Add(seed)
+	f.Add([]byte{binaryVersion, binaryFlagFrames})
+	f.Add([]byte{binaryVersion, binaryFlagIdentity, 0, 0, 0x01})
+	f.Add([]byte{binaryVersion, 0, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0x01})
+	f.Fuzz(func(t *testing.T, data []byte) {
+		e := &Error{}
+		if err := e.UnmarshalBinary(data); err != nil {
+			if !errors.Is(err, ErrInvalidBinary) {
+				t.Fatalf("expecting %s, got %s", ErrInvalidBinary, err)
+			}
+			return
+		}
+		buf, err := e.MarshalBinaryWith(BinaryOptions{Frames: true})
+		if err != nil {
+			t.Fatalf("expecting nil, got %s", err)
+		}
+		got := &Error{}
+		if err = got.UnmarshalBinary(buf); err != nil {
+			t.Fatalf("expecting re-encoded data to decode, got %s", err)
+		}
+		if !reflect.DeepEqual(e, got) {
+			t.Fatalf("expecting %+v, got %+v", e, got)
+		}
+	})
+}
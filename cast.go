@@ -4,21 +4,39 @@
 
 package errors
 
-import "fmt"
+import (
+	"errors"
+	"fmt"
+)
 
 // Code returns the code of the root error, if available.
-// Otherwise, returns INTERNAL.
-func Code(err error) string {
+// If no Error is present within the chain, the code is
+// obtained by Classify. Otherwise, returns INTERNAL.
+func Code(err error) ErrorCode {
 	if err == nil {
 		return ""
 	} else if e, ok := err.(*Error); ok && e.Code != "" {
 		return e.Code
 	} else if ok && e.Err != nil {
 		return Code(e.Err)
+	} else if !ok && errors.As(err, &e) {
+		return Code(e)
+	} else if code := Classify(err); code != "" {
+		return code
 	}
 	return INTERNAL
 }
 
+// HasCode reports whether the code of the error is equal
+// to code or is one of its sub-codes, for example an error
+// with the code "not_found.user" has the code NOTFOUND.
+func HasCode(err error, code ErrorCode) bool {
+	if err == nil {
+		return false
+	}
+	return Code(err).Within(code)
+}
+
 // Message returns the human-readable message of the error,
 // if available. Otherwise, returns a generic error
 // message.
@@ -30,22 +48,82 @@ func Message(err error) string {
 	} else if ok && e.Err != nil {
 		return Message(e.Err)
 	}
-	return GlobalError
+	return globalMessage()
+}
+
+// Operation returns the outermost operation within the
+// chain, if available.
+func Operation(err error) string {
+	for ; err != nil; err = Unwrap(err) {
+		if e, ok := err.(*Error); ok && e.Operation != "" {
+			return e.Operation
+		}
+	}
+	return ""
+}
+
+// Metadata returns the merged metadata of every Error in
+// the chain. Keys set on outer errors take precedence over
+// the ones they wrap.
+func Metadata(err error) map[string]string {
+	meta := make(map[string]string)
+	for ; err != nil; err = errors.Unwrap(err) {
+		e, ok := err.(*Error)
+		if !ok {
+			continue
+		}
+		for k, v := range e.Metadata {
+			if _, exists := meta[k]; !exists {
+				meta[k] = v
+			}
+		}
+	}
+	return meta
 }
 
-// ToError Returns an application error from input. If The type
-// is not of type Error, nil will be returned.
+// ToError returns an application error from the input,
+// the conversion rules are as follows:
+//
+//   - nil values return nil.
+//   - An Error is returned as is.
+//   - An error containing an Error within its chain returns
+//     the outermost Error found by errors.As.
+//   - Any other error is wrapped, so the original value
+//     is preserved for errors.Is and errors.As.
+//   - Strings and fmt.Stringer values are wrapped verbatim,
+//     they are not treated as a format.
+//   - A slice of errors is joined with errors.Join and
+//     wrapped, returning nil if every error is nil.
+//   - Any other value, such as one obtained from recover(),
+//     is formatted with %v and wrapped.
+//
+// Errors created by ToError capture the stack at the point
+// of conversion.
 func ToError(err any) *Error {
 	switch v := err.(type) {
+	case nil:
+		return nil
 	case *Error:
 		return v
 	case Error:
 		return &v
 	case error:
-		return &Error{Err: fmt.Errorf(v.Error())}
+		var e *Error
+		if errors.As(v, &e) && e != nil {
+			return e
+		}
+		return newError(v, "", "", "")
+	case fmt.Stringer:
+		return newError(errors.New(v.String()), "", "", "")
 	case string:
-		return &Error{Err: fmt.Errorf(v)}
+		return newError(errors.New(v), "", "", "")
+	case []error:
+		joined := errors.Join(v...)
+		if joined == nil {
+			return nil
+		}
+		return newError(joined, "", "", "")
 	default:
-		return nil
+		return newError(fmt.Errorf("%v", v), "", "", "")
 	}
 }
@@ -5,15 +5,19 @@
 package errors
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
+	"os"
 	"reflect"
+	"strings"
 	"testing"
 )
 
 func TestError_Code(t *testing.T) {
 	tt := map[string]struct {
 		input error
-		want  string
+		want  ErrorCode
 	}{
 		"Normal": {
 			&Error{Code: INTERNAL, Message: "test", Operation: "op", Err: fmt.Errorf("err")},
@@ -27,6 +31,18 @@ func TestError_Code(t *testing.T) {
 			&Error{Code: "", Message: "test", Operation: "op", Err: fmt.Errorf("err")},
 			"internal",
 		},
+		"Wrapped Error": {
+			fmt.Errorf("wrap: %w", &Error{Code: CONFLICT}),
+			CONFLICT,
+		},
+		"Classified": {
+			sql.ErrNoRows,
+			NOTFOUND,
+		},
+		"Classified Cause": {
+			&Error{Message: "test", Err: fmt.Errorf("wrap: %w", sql.ErrNoRows)},
+			NOTFOUND,
+		},
 	}
 
 	for name, test := range tt {
@@ -68,36 +84,230 @@ func Test_Message(t *testing.T) {
 	}
 }
 
+type testStringer struct{}
+
+func (testStringer) String() string { return "stringer 100%" }
+
 func TestError_ToError(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed: %s", err.Error())
+	}
+
+	inner := &Error{Code: NOTFOUND, Message: "test", Operation: "op", Err: sql.ErrNoRows}
+	outer := &Error{Code: INTERNAL, Message: "outer", Err: fmt.Errorf("wrap: %w", inner)}
+
 	tt := map[string]struct {
 		input any
 		want  *Error
+		err   string
 	}{
 		"Pointer": {
 			&Error{Code: INTERNAL, Message: "test", Operation: "op", Err: fmt.Errorf("err")},
 			&Error{Code: INTERNAL, Message: "test", Operation: "op", Err: fmt.Errorf("err")},
+			"err",
 		},
 		"Non Pointer": {
 			Error{Code: INTERNAL, Message: "test", Operation: "op", Err: fmt.Errorf("err")},
 			&Error{Code: INTERNAL, Message: "test", Operation: "op", Err: fmt.Errorf("err")},
+			"err",
+		},
+		"Nil Pointer": {
+			(*Error)(nil),
+			nil,
+			"",
 		},
 		"Error": {
-			fmt.Errorf("err"),
-			&Error{Err: fmt.Errorf("err")},
+			sql.ErrNoRows,
+			&Error{Err: sql.ErrNoRows},
+			sql.ErrNoRows.Error(),
+		},
+		"Wrapped Error": {
+			fmt.Errorf("wrap: %w", inner),
+			inner,
+			inner.Err.Error(),
+		},
+		"Outermost Error": {
+			fmt.Errorf("wrap: %w", outer),
+			outer,
+			outer.Err.Error(),
 		},
 		"String": {
-			"err",
-			&Error{Err: fmt.Errorf("err")},
+			"100% err",
+			&Error{Err: errors.New("100% err")},
+			"100% err",
+		},
+		"Stringer": {
+			testStringer{},
+			&Error{Err: errors.New("stringer 100%")},
+			"stringer 100%",
+		},
+		"Errors": {
+			[]error{sql.ErrNoRows, nil, fmt.Errorf("err")},
+			&Error{Err: errors.Join(sql.ErrNoRows, fmt.Errorf("err"))},
+			"sql: no rows in result set\nerr",
+		},
+		"Nil Errors": {
+			[]error{nil},
+			nil,
+			"",
+		},
+		"Panic Value": {
+			42,
+			&Error{Err: fmt.Errorf("42")},
+			"42",
 		},
 		"Default": {
 			nil,
 			nil,
+			"",
 		},
 	}
 
 	for name, test := range tt {
 		t.Run(name, func(t *testing.T) {
 			got := ToError(test.input)
+			if test.want == nil {
+				if got != nil {
+					t.Fatalf("expecting nil, got %s", got)
+				}
+				return
+			}
+			if got.Code != test.want.Code || got.Message != test.want.Message || got.Operation != test.want.Operation {
+				t.Fatalf("expecting %+v, got %+v", test.want, got)
+			}
+			if !reflect.DeepEqual(test.err, got.Err.Error()) {
+				t.Fatalf("expecting %s, got %s", test.err, got.Err.Error())
+			}
+		})
+	}
+
+	t.Run("Preserves Chain", func(t *testing.T) {
+		got := ToError(fmt.Errorf("wrap: %w", sql.ErrNoRows))
+		if !Is(got, sql.ErrNoRows) {
+			t.Fatalf("expecting %s to wrap %s", got, sql.ErrNoRows)
+		}
+	})
+
+	t.Run("Captures Stack", func(t *testing.T) {
+		got := ToError(sql.ErrNoRows)
+		want := wd + "/cast_test.go:193"
+		if !reflect.DeepEqual(want, got.FileLine()) {
+			t.Fatalf("expecting %s, got %s", want, got.FileLine())
+		}
+		frame, _ := got.RuntimeFrames().Next()
+		if !strings.HasPrefix(frame.Function, "github.com/ainsleyclark/errors.TestError_ToError.") {
+			t.Fatalf("expecting test function, got %s", frame.Function)
+		}
+	})
+}
+
+func TestMetadata(t *testing.T) {
+	tt := map[string]struct {
+		input error
+		want  map[string]string
+	}{
+		"Nil Input": {
+			nil,
+			map[string]string{},
+		},
+		"No Metadata": {
+			fmt.Errorf("err"),
+			map[string]string{},
+		},
+		"Merged": {
+			fmt.Errorf("wrap: %w", &Error{
+				Metadata: map[string]string{"a": "outer", "b": "b"},
+				Err:      &Error{Metadata: map[string]string{"a": "inner", "c": "c"}},
+			}),
+			map[string]string{"a": "outer", "b": "b", "c": "c"},
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := Metadata(test.input)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestHasCode(t *testing.T) {
+	tt := map[string]struct {
+		input error
+		code  ErrorCode
+		want  bool
+	}{
+		"Nil": {
+			nil,
+			INTERNAL,
+			false,
+		},
+		"Equal": {
+			NewNotFound(nil, "message", "op"),
+			NOTFOUND,
+			true,
+		},
+		"Sub Code": {
+			&Error{Code: "not_found.user"},
+			NOTFOUND,
+			true,
+		},
+		"Wrapped Sub Code": {
+			fmt.Errorf("wrap: %w", &Error{Code: "invalid.email.format"}),
+			"invalid.email",
+			true,
+		},
+		"Different Code": {
+			&Error{Code: "not_found.user"},
+			INVALID,
+			false,
+		},
+		"More Specific": {
+			NewNotFound(nil, "message", "op"),
+			"not_found.user",
+			false,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := HasCode(test.input, test.code)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %t, got %t", test.want, got)
+			}
+		})
+	}
+}
+
+func TestOperation(t *testing.T) {
+	tt := map[string]struct {
+		input error
+		want  string
+	}{
+		"Nil": {
+			nil,
+			"",
+		},
+		"Standard Error": {
+			fmt.Errorf("err"),
+			"",
+		},
+		"Outermost": {
+			&Error{Operation: "outer", Err: &Error{Operation: "inner"}},
+			"outer",
+		},
+		"Skips Empty": {
+			fmt.Errorf("wrap: %w", &Error{Err: &Error{Operation: "inner"}}),
+			"inner",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := Operation(test.input)
 			if !reflect.DeepEqual(test.want, got) {
 				t.Fatalf("expecting %s, got %s", test.want, got)
 			}
@@ -0,0 +1,101 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "errors"
+
+// AsType finds the first error in the chain that matches
+// the type T, in the same manner as As, without requiring
+// a pointer variable.
+func AsType[T error](err error) (T, bool) {
+	var target T
+	ok := errors.As(err, &target)
+	return target, ok
+}
+
+// Walk calls fn for err and every error it wraps, depth
+// first, following both Unwrap() error and Unwrap() []error.
+// The walk stops once fn returns false.
+func Walk(err error, fn func(error) bool) {
+	walk(err, fn)
+}
+
+// walk is the recursive implementation of Walk, returning
+// false once the walk has been stopped.
+func walk(err error, fn func(error) bool) bool {
+	if err == nil {
+		return true
+	}
+	if !fn(err) {
+		return false
+	}
+	switch x := err.(type) {
+	case interface{ Unwrap() error }:
+		return walk(x.Unwrap(), fn)
+	case interface{ Unwrap() []error }:
+		for _, e := range x.Unwrap() {
+			if !walk(e, fn) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// Find returns the first Error within the tree for which
+// pred returns true, or nil if there is none.
+func Find(err error, pred func(*Error) bool) *Error {
+	var found *Error
+	Walk(err, func(err error) bool {
+		if e, ok := err.(*Error); ok && pred(e) {
+			found = e
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// All returns every error within the tree of type T, in
+// the order they are visited by Walk.
+func All[T error](err error) []T {
+	var all []T
+	Walk(err, func(err error) bool {
+		if t, ok := err.(T); ok {
+			all = append(all, t)
+		}
+		return true
+	})
+	return all
+}
+
+// Root returns the innermost error of the chain by
+// repeatedly unwrapping err. For errors wrapping several
+// errors, the first is followed. If err is nil, Root
+// returns nil.
+func Root(err error) error {
+	for err != nil {
+		var next error
+		switch x := err.(type) {
+		case interface{ Unwrap() error }:
+			next = x.Unwrap()
+		case interface{ Unwrap() []error }:
+			if errs := x.Unwrap(); len(errs) > 0 {
+				next = errs[0]
+			}
+		}
+		if next == nil {
+			return err
+		}
+		err = next
+	}
+	return nil
+}
+
+// Cause is an alias of Root, returning the innermost error
+// of the chain.
+func Cause(err error) error {
+	return Root(err)
+}
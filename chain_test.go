@@ -0,0 +1,164 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"reflect"
+	"testing"
+)
+
+func TestAsType(t *testing.T) {
+	pathErr := &fs.PathError{Op: "open", Path: "file", Err: fs.ErrNotExist}
+
+	tt := map[string]struct {
+		input error
+		want  any
+	}{
+		"Nil": {
+			nil,
+			nil,
+		},
+		"Direct": {
+			pathErr,
+			pathErr,
+		},
+		"Wrapped": {
+			NewNotFound(fmt.Errorf("wrap: %w", pathErr), "message", "op"),
+			pathErr,
+		},
+		"Joined": {
+			errors.Join(fmt.Errorf("error"), pathErr),
+			pathErr,
+		},
+		"Missing": {
+			NewInternal(nil, "message", "op"),
+			nil,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got, ok := AsType[*fs.PathError](test.input)
+			if test.want == nil {
+				if ok {
+					t.Fatalf("expecting no match, got %v", got)
+				}
+				return
+			}
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestWalk(t *testing.T) {
+	a, b, c := errors.New("a"), errors.New("b"), errors.New("c")
+	tree := fmt.Errorf("root: %w", errors.Join(a, fmt.Errorf("b: %w", b), c))
+
+	tt := map[string]struct {
+		stop error
+		want int
+	}{
+		"Complete": {nil, 6},
+		"Stopped":  {b, 5},
+		"First":    {tree, 1},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			var visited int
+			Walk(tree, func(err error) bool {
+				visited++
+				return err != test.stop
+			})
+			if !reflect.DeepEqual(test.want, visited) {
+				t.Fatalf("expecting %d, got %d", test.want, visited)
+			}
+		})
+	}
+}
+
+func TestWalk_Nil(t *testing.T) {
+	Walk(nil, func(err error) bool {
+		t.Fatal("expecting fn not to be called")
+		return true
+	})
+}
+
+func TestFind(t *testing.T) {
+	notFound := NewNotFound(nil, "message", "Store.Find")
+	err := errors.Join(NewInternal(nil, "message", "Handler"), fmt.Errorf("wrap: %w", notFound))
+
+	got := Find(err, func(e *Error) bool { return e.Code == NOTFOUND })
+	if got != notFound {
+		t.Fatalf("expecting %s, got %v", notFound, got)
+	}
+	if got = Find(err, func(e *Error) bool { return e.Code == CONFLICT }); got != nil {
+		t.Fatalf("expecting nil, got %s", got)
+	}
+}
+
+func TestAll(t *testing.T) {
+	inner := NewNotFound(nil, "inner", "op")
+	outer := NewInternal(inner, "outer", "op")
+	other := NewInvalid(nil, "other", "op")
+	err := errors.Join(outer, fmt.Errorf("error"), other)
+
+	got := All[*Error](err)
+	want := []*Error{outer, inner, other}
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expecting %v, got %v", want, got)
+	}
+	if got := All[*fs.PathError](err); got != nil {
+		t.Fatalf("expecting nil, got %v", got)
+	}
+}
+
+func TestRoot(t *testing.T) {
+	root := errors.New("root")
+	unwrapped := NewInternal(nil, "message", "op")
+
+	tt := map[string]struct {
+		input error
+		want  error
+	}{
+		"Nil": {
+			nil,
+			nil,
+		},
+		"Unwrapped": {
+			root,
+			root,
+		},
+		"Chain": {
+			NewInternal(fmt.Errorf("wrap: %w", root), "message", "op"),
+			root,
+		},
+		"Joined": {
+			errors.Join(fmt.Errorf("wrap: %w", root), errors.New("other")),
+			root,
+		},
+		"No Wrapped Error": {
+			unwrapped,
+			unwrapped,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := Root(test.input)
+			if got != test.want {
+				t.Fatalf("expecting %v, got %v", test.want, got)
+			}
+			if Cause(test.input) != got {
+				t.Fatalf("expecting cause to equal root")
+			}
+		})
+	}
+}
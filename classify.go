@@ -0,0 +1,97 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net"
+	"strconv"
+	"sync"
+)
+
+// Classifier returns the error code for an error that is
+// not an Error, or an empty string if it doesn't recognise
+// the error.
+type Classifier func(err error) ErrorCode
+
+var (
+	classifiersMtx sync.RWMutex
+	// classifiers are the user registered classifiers, they
+	// are checked in order before the standard library ones.
+	classifiers []Classifier
+)
+
+// RegisterClassifier adds a classifier used by Classify,
+// allowing third party errors to be mapped to error codes.
+// Registered classifiers take precedence over the built-in
+// ones, in the order they were registered.
+func RegisterClassifier(c Classifier) {
+	classifiersMtx.Lock()
+	defer classifiersMtx.Unlock()
+	classifiers = append(classifiers, c)
+}
+
+// Classify returns the error code for common standard library
+// errors within the chain, or an empty string if the error
+// is not recognised. It's used by Code when no Error is
+// present.
+//
+//   - sql.ErrNoRows and fs.ErrNotExist return NOTFOUND.
+//   - fs.ErrExist returns CONFLICT.
+//   - context.DeadlineExceeded and net.Error timeouts return
+//     DEADLINE_EXCEEDED.
+//   - context.Canceled returns CANCELLED.
+//   - *json.SyntaxError, *json.UnmarshalTypeError and
+//     *strconv.NumError return INVALID.
+func Classify(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+
+	classifiersMtx.RLock()
+	cs := classifiers
+	classifiersMtx.RUnlock()
+
+	for _, c := range cs {
+		if code := c(err); code != "" {
+			return code
+		}
+	}
+
+	return classifyStd(err)
+}
+
+// classifyStd classifies errors from the standard library.
+func classifyStd(err error) ErrorCode {
+	switch {
+	case errors.Is(err, sql.ErrNoRows), errors.Is(err, fs.ErrNotExist):
+		return NOTFOUND
+	case errors.Is(err, fs.ErrExist):
+		return CONFLICT
+	case errors.Is(err, context.DeadlineExceeded):
+		return DEADLINE_EXCEEDED
+	case errors.Is(err, context.Canceled):
+		return CANCELLED
+	}
+
+	var (
+		syntaxErr *json.SyntaxError
+		typeErr   *json.UnmarshalTypeError
+		numErr    *strconv.NumError
+		netErr    net.Error
+	)
+	switch {
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr), errors.As(err, &numErr):
+		return INVALID
+	case errors.As(err, &netErr) && netErr.Timeout():
+		return DEADLINE_EXCEEDED
+	}
+
+	return ""
+}
@@ -0,0 +1,95 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+type timeoutError struct{}
+
+func (timeoutError) Error() string   { return "i/o timeout" }
+func (timeoutError) Timeout() bool   { return true }
+func (timeoutError) Temporary() bool { return true }
+
+var _ net.Error = timeoutError{}
+
+func TestClassify(t *testing.T) {
+	_, numErr := strconv.Atoi("a")
+	_, openErr := os.Open("does-not-exist")
+	syntaxErr := json.Unmarshal([]byte("{"), &struct{}{})
+	typeErr := json.Unmarshal([]byte(`{"a":"b"}`), &struct{ A int }{})
+
+	tt := map[string]struct {
+		input error
+		want  ErrorCode
+	}{
+		"Nil":               {nil, ""},
+		"Unknown":           {fmt.Errorf("error"), ""},
+		"No Rows":           {sql.ErrNoRows, NOTFOUND},
+		"Wrapped No Rows":   {fmt.Errorf("wrap: %w", sql.ErrNoRows), NOTFOUND},
+		"Not Exist":         {fs.ErrNotExist, NOTFOUND},
+		"Open":              {openErr, NOTFOUND},
+		"Exist":             {fs.ErrExist, CONFLICT},
+		"Deadline Exceeded": {context.DeadlineExceeded, DEADLINE_EXCEEDED},
+		"Cancelled":         {context.Canceled, CANCELLED},
+		"JSON Syntax":       {syntaxErr, INVALID},
+		"JSON Type":         {typeErr, INVALID},
+		"Strconv":           {numErr, INVALID},
+		"Net Timeout":       {&net.OpError{Op: "read", Err: timeoutError{}}, DEADLINE_EXCEEDED},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := Classify(test.input)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestRegisterClassifier(t *testing.T) {
+	t.Cleanup(func() {
+		classifiersMtx.Lock()
+		classifiers = nil
+		classifiersMtx.Unlock()
+	})
+
+	custom := fmt.Errorf("custom")
+	RegisterClassifier(func(err error) ErrorCode {
+		if Is(err, custom) || Is(err, sql.ErrNoRows) {
+			return EXPIRED
+		}
+		return ""
+	})
+
+	tt := map[string]struct {
+		input error
+		want  ErrorCode
+	}{
+		"Custom":   {custom, EXPIRED},
+		"Override": {sql.ErrNoRows, EXPIRED},
+		"Fallback": {fs.ErrExist, CONFLICT},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := Classify(test.input)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,229 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// ErrorCode defines an application error code, such as
+// NOTFOUND. The type is named ErrorCode as Code is used
+// for obtaining the code of an error.
+//
+// Codes may be namespaced with CodeSeparator to describe
+// domain-specific errors, such as "not_found.user", which
+// inherit the behaviour of their nearest registered parent.
+type ErrorCode string
+
+// CodeSeparator separates the segments of a hierarchical
+// error code.
+const CodeSeparator = "."
+
+// StatusClientClosedRequest is the non-standard HTTP status
+// used when the client cancels the request.
+const StatusClientClosedRequest = 499
+
+var (
+	codesMtx sync.RWMutex
+	// codes maps the known error codes to their HTTP
+	// status.
+	codes = map[ErrorCode]int{
+		CONFLICT:             http.StatusConflict,
+		INTERNAL:             http.StatusInternalServerError,
+		INVALID:              http.StatusBadRequest,
+		NOTFOUND:             http.StatusNotFound,
+		UNKNOWN:              http.StatusInternalServerError,
+		MAXIMUMATTEMPTS:      http.StatusTooManyRequests,
+		EXPIRED:              http.StatusPaymentRequired,
+		CANCELLED:            StatusClientClosedRequest,
+		DEADLINE_EXCEEDED:    http.StatusGatewayTimeout,
+		UNAUTHENTICATED:      http.StatusUnauthorized,
+		PERMISSION_DENIED:    http.StatusForbidden,
+		UNAVAILABLE:          http.StatusServiceUnavailable,
+		UNIMPLEMENTED:        http.StatusNotImplemented,
+		FAILED_PRECONDITION:  http.StatusBadRequest,
+		ALREADY_EXISTS:       http.StatusConflict,
+		ABORTED:              http.StatusConflict,
+		OUT_OF_RANGE:         http.StatusBadRequest,
+		DATA_LOSS:            http.StatusInternalServerError,
+		RESOURCE_EXHAUSTED:   http.StatusTooManyRequests,
+		METHODNOTALLOWED:     http.StatusMethodNotAllowed,
+		REQUESTTIMEOUT:       http.StatusRequestTimeout,
+		GONE:                 http.StatusGone,
+		PRECONDITIONFAILED:   http.StatusPreconditionFailed,
+		TOOLARGE:             http.StatusRequestEntityTooLarge,
+		UNSUPPORTEDMEDIATYPE: http.StatusUnsupportedMediaType,
+		UNPROCESSABLE:        http.StatusUnprocessableEntity,
+		BADGATEWAY:           http.StatusBadGateway,
+	}
+	// preferred defines the code returned by CodeForHTTPStatus
+	// when several codes share a status.
+	preferred = map[int]ErrorCode{
+		http.StatusBadRequest:          INVALID,
+		http.StatusConflict:            CONFLICT,
+		http.StatusTooManyRequests:     RESOURCE_EXHAUSTED,
+		http.StatusInternalServerError: INTERNAL,
+	}
+)
+
+// HTTPStatusKey is the metadata key used for the original
+// HTTP status of errors created by FromHTTPStatus.
+const HTTPStatusKey = "http_status"
+
+// RegisterCode adds a custom error code with the HTTP status
+// returned by HTTPStatus, or replaces the status of an
+// existing code. Registered codes are considered valid and
+// are returned by CodeForHTTPStatus for the status. Top-level
+// codes take precedence over codes registered before them,
+// sub-codes are only returned when the status has no
+// top-level code.
+func RegisterCode(code ErrorCode, status int) {
+	codesMtx.Lock()
+	defer codesMtx.Unlock()
+	codes[code] = status
+	if !strings.Contains(string(code), CodeSeparator) {
+		preferred[status] = code
+	}
+}
+
+// CodeForHTTPStatus returns the error code for the HTTP
+// status, using the same table as HTTPStatus so the two
+// directions agree. Where several codes share a status, the
+// most generic is returned, for example CONFLICT for a 409.
+// Statuses without a code fall back to INVALID for 4xx and
+// INTERNAL for 5xx, any other status returns UNKNOWN.
+func CodeForHTTPStatus(status int) ErrorCode {
+	codesMtx.RLock()
+	defer codesMtx.RUnlock()
+	if code, ok := preferred[status]; ok && codes[code] == status {
+		return code
+	}
+	var match ErrorCode
+	for code, s := range codes {
+		if s == status && (match == "" || lessCode(code, match)) {
+			match = code
+		}
+	}
+	switch {
+	case match != "":
+		return match
+	case status >= 400 && status < 500:
+		return INVALID
+	case status >= 500 && status < 600:
+		return INTERNAL
+	}
+	return UNKNOWN
+}
+
+// FromHTTPStatus returns an Error with the code obtained
+// from CodeForHTTPStatus and the given message. The status
+// is stored in the metadata under HTTPStatusKey, as it may
+// not be recoverable from the code.
+func FromHTTPStatus(status int, message string) *Error {
+	return newError(nil, message, CodeForHTTPStatus(status), "").
+		WithMeta(HTTPStatusKey, strconv.Itoa(status))
+}
+
+// lessCode orders codes by depth then name, so top-level
+// codes are preferred over their sub-codes.
+func lessCode(a, b ErrorCode) bool {
+	da, db := strings.Count(string(a), CodeSeparator), strings.Count(string(b), CodeSeparator)
+	if da != db {
+		return da < db
+	}
+	return a < b
+}
+
+// String implements fmt.Stringer on the error code.
+func (c ErrorCode) String() string {
+	return string(c)
+}
+
+// Parent returns the code with its last segment removed,
+// for example "invalid.email" for "invalid.email.format".
+// If the code has no parent, an empty code is returned.
+func (c ErrorCode) Parent() ErrorCode {
+	i := strings.LastIndex(string(c), CodeSeparator)
+	if i < 0 {
+		return ""
+	}
+	return c[:i]
+}
+
+// Category returns the top-level segment of the code, for
+// example "not_found" for "not_found.user".
+func (c ErrorCode) Category() ErrorCode {
+	if i := strings.Index(string(c), CodeSeparator); i >= 0 {
+		return c[:i]
+	}
+	return c
+}
+
+// Within reports whether the code is equal to parent or is
+// one of its sub-codes, for example "not_found.user" is
+// within NOTFOUND, but "not_founder" is not.
+func (c ErrorCode) Within(parent ErrorCode) bool {
+	if parent == "" {
+		return c == ""
+	}
+	return c == parent || strings.HasPrefix(string(c), string(parent)+CodeSeparator)
+}
+
+// Valid reports whether the code is known, either as one of
+// the package's codes or one added by RegisterCode. Sub-codes
+// are valid when one of their parents is known.
+func (c ErrorCode) Valid() bool {
+	_, ok := c.status()
+	return ok
+}
+
+// HTTPStatus returns the HTTP response status code for the
+// error code, falling back to the status of the nearest
+// registered parent. Unknown codes return a 500.
+func (c ErrorCode) HTTPStatus() int {
+	if status, ok := c.status(); ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// status returns the HTTP status registered for the code
+// or its nearest parent.
+func (c ErrorCode) status() (int, bool) {
+	codesMtx.RLock()
+	defer codesMtx.RUnlock()
+	for ; c != ""; c = c.Parent() {
+		if status, ok := codes[c]; ok {
+			return status, true
+		}
+	}
+	return 0, false
+}
+
+// MarshalText implements encoding.TextMarshaler, returning
+// an error if the code is not valid. An empty code is
+// marshalled as an empty string.
+func (c ErrorCode) MarshalText() ([]byte, error) {
+	if c != "" && !c.Valid() {
+		return nil, fmt.Errorf("errors: unknown error code %q", string(c))
+	}
+	return []byte(c), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler,
+// returning an error if the code is not valid. An empty
+// string is unmarshalled as an empty code.
+func (c *ErrorCode) UnmarshalText(text []byte) error {
+	code := ErrorCode(text)
+	if code != "" && !code.Valid() {
+		return fmt.Errorf("errors: unknown error code %q", string(text))
+	}
+	*c = code
+	return nil
+}
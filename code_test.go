@@ -0,0 +1,344 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestErrorCode_String(t *testing.T) {
+	got := NOTFOUND.String()
+	want := "not_found"
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
+
+func TestErrorCode_Valid(t *testing.T) {
+	tt := map[string]struct {
+		input ErrorCode
+		want  bool
+	}{
+		"Known":   {NOTFOUND, true},
+		"Typo":    {"notfound", false},
+		"Empty":   {"", false},
+		"Unknown": {"not-found", false},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := test.input.Valid()
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %t, got %t", test.want, got)
+			}
+		})
+	}
+}
+
+func TestErrorCode_HTTPStatus(t *testing.T) {
+	tt := map[string]struct {
+		input ErrorCode
+		want  int
+	}{
+		"Conflict":            {CONFLICT, http.StatusConflict},
+		"Internal":            {INTERNAL, http.StatusInternalServerError},
+		"Invalid":             {INVALID, http.StatusBadRequest},
+		"Not Found":           {NOTFOUND, http.StatusNotFound},
+		"Unknown":             {UNKNOWN, http.StatusInternalServerError},
+		"Maximum Attempts":    {MAXIMUMATTEMPTS, http.StatusTooManyRequests},
+		"Expired":             {EXPIRED, http.StatusPaymentRequired},
+		"Cancelled":           {CANCELLED, StatusClientClosedRequest},
+		"Deadline Exceeded":   {DEADLINE_EXCEEDED, http.StatusGatewayTimeout},
+		"Unauthenticated":     {UNAUTHENTICATED, http.StatusUnauthorized},
+		"Permission Denied":   {PERMISSION_DENIED, http.StatusForbidden},
+		"Unavailable":         {UNAVAILABLE, http.StatusServiceUnavailable},
+		"Unimplemented":       {UNIMPLEMENTED, http.StatusNotImplemented},
+		"Failed Precondition": {FAILED_PRECONDITION, http.StatusBadRequest},
+		"Already Exists":      {ALREADY_EXISTS, http.StatusConflict},
+		"Aborted":             {ABORTED, http.StatusConflict},
+		"Out Of Range":        {OUT_OF_RANGE, http.StatusBadRequest},
+		"Data Loss":           {DATA_LOSS, http.StatusInternalServerError},
+		"Resource Exhausted":  {RESOURCE_EXHAUSTED, http.StatusTooManyRequests},
+		"Unregistered":        {"unregistered", http.StatusInternalServerError},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := test.input.HTTPStatus()
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %d, got %d", test.want, got)
+			}
+		})
+	}
+}
+
+func TestRegisterCode(t *testing.T) {
+	const code ErrorCode = "payment_required"
+	t.Cleanup(func() {
+		codesMtx.Lock()
+		delete(codes, code)
+		codesMtx.Unlock()
+	})
+
+	if code.Valid() {
+		t.Fatalf("expecting %s to be invalid before registering", code)
+	}
+	RegisterCode(code, http.StatusPaymentRequired)
+	if !code.Valid() {
+		t.Fatalf("expecting %s to be valid after registering", code)
+	}
+	if got := code.HTTPStatus(); got != http.StatusPaymentRequired {
+		t.Fatalf("expecting %d, got %d", http.StatusPaymentRequired, got)
+	}
+}
+
+func TestErrorCode_MarshalText(t *testing.T) {
+	tt := map[string]struct {
+		input ErrorCode
+		want  string
+	}{
+		"Known":   {NOTFOUND, `"not_found"`},
+		"Empty":   {"", `""`},
+		"Unknown": {"notfound", `unknown error code "notfound"`},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got, err := json.Marshal(test.input)
+			if err != nil {
+				if !strings.Contains(err.Error(), test.want) {
+					t.Fatalf("expecting %s to contain, got %s", test.want, err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(test.want, string(got)) {
+				t.Fatalf("expecting %s, got %s", test.want, string(got))
+			}
+		})
+	}
+}
+
+func TestErrorCode_UnmarshalText(t *testing.T) {
+	tt := map[string]struct {
+		input string
+		want  any
+	}{
+		"Known":   {`"not_found"`, NOTFOUND},
+		"Empty":   {`""`, ErrorCode("")},
+		"Unknown": {`"not-found"`, `unknown error code "not-found"`},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			var got ErrorCode
+			err := json.Unmarshal([]byte(test.input), &got)
+			if err != nil {
+				if !strings.Contains(err.Error(), fmt.Sprintf("%s", test.want)) {
+					t.Fatalf("expecting %s to contain, got %s", test.want, err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestErrorCode_Parent(t *testing.T) {
+	tt := map[string]struct {
+		input ErrorCode
+		want  ErrorCode
+	}{
+		"Top Level": {NOTFOUND, ""},
+		"Sub Code":  {"not_found.user", NOTFOUND},
+		"Nested":    {"invalid.email.format", "invalid.email"},
+		"Empty":     {"", ""},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := test.input.Parent()
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestErrorCode_Category(t *testing.T) {
+	tt := map[string]struct {
+		input ErrorCode
+		want  ErrorCode
+	}{
+		"Top Level": {NOTFOUND, NOTFOUND},
+		"Sub Code":  {"not_found.user", NOTFOUND},
+		"Nested":    {"invalid.email.format", INVALID},
+		"HTTP":      {METHODNOTALLOWED, INVALID},
+		"Empty":     {"", ""},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := test.input.Category()
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestErrorCode_Within(t *testing.T) {
+	tt := map[string]struct {
+		input  ErrorCode
+		parent ErrorCode
+		want   bool
+	}{
+		"Equal":        {NOTFOUND, NOTFOUND, true},
+		"Sub Code":     {"not_found.user", NOTFOUND, true},
+		"Nested":       {"invalid.email.format", "invalid.email", true},
+		"Shared Start": {"not_founder", NOTFOUND, false},
+		"Child":        {NOTFOUND, "not_found.user", false},
+		"Empty Parent": {NOTFOUND, "", false},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := test.input.Within(test.parent)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %t, got %t", test.want, got)
+			}
+		})
+	}
+}
+
+func TestErrorCode_Hierarchy(t *testing.T) {
+	const code ErrorCode = "invalid.email.format"
+	t.Cleanup(func() {
+		codesMtx.Lock()
+		delete(codes, "invalid.email")
+		codesMtx.Unlock()
+	})
+
+	if !code.Valid() {
+		t.Fatalf("expecting %s to be valid", code)
+	}
+	if got := code.HTTPStatus(); got != http.StatusBadRequest {
+		t.Fatalf("expecting %d, got %d", http.StatusBadRequest, got)
+	}
+	if ErrorCode("notfound.user").Valid() {
+		t.Fatal("expecting sub-code of unknown code to be invalid")
+	}
+
+	RegisterCode("invalid.email", http.StatusUnprocessableEntity)
+	if got := code.HTTPStatus(); got != http.StatusUnprocessableEntity {
+		t.Fatalf("expecting %d, got %d", http.StatusUnprocessableEntity, got)
+	}
+}
+
+func TestCodeForHTTPStatus(t *testing.T) {
+	tt := map[string]struct {
+		input int
+		want  ErrorCode
+	}{
+		"Bad Request":            {http.StatusBadRequest, INVALID},
+		"Unauthorized":           {http.StatusUnauthorized, UNAUTHENTICATED},
+		"Payment Required":       {http.StatusPaymentRequired, EXPIRED},
+		"Forbidden":              {http.StatusForbidden, PERMISSION_DENIED},
+		"Not Found":              {http.StatusNotFound, NOTFOUND},
+		"Method Not Allowed":     {http.StatusMethodNotAllowed, METHODNOTALLOWED},
+		"Request Timeout":        {http.StatusRequestTimeout, REQUESTTIMEOUT},
+		"Conflict":               {http.StatusConflict, CONFLICT},
+		"Gone":                   {http.StatusGone, GONE},
+		"Precondition Failed":    {http.StatusPreconditionFailed, PRECONDITIONFAILED},
+		"Too Large":              {http.StatusRequestEntityTooLarge, TOOLARGE},
+		"Unsupported Media Type": {http.StatusUnsupportedMediaType, UNSUPPORTEDMEDIATYPE},
+		"Unprocessable":          {http.StatusUnprocessableEntity, UNPROCESSABLE},
+		"Too Many Requests":      {http.StatusTooManyRequests, RESOURCE_EXHAUSTED},
+		"Client Closed":          {StatusClientClosedRequest, CANCELLED},
+		"Teapot":                 {http.StatusTeapot, INVALID},
+		"Internal":               {http.StatusInternalServerError, INTERNAL},
+		"Not Implemented":        {http.StatusNotImplemented, UNIMPLEMENTED},
+		"Bad Gateway":            {http.StatusBadGateway, BADGATEWAY},
+		"Service Unavailable":    {http.StatusServiceUnavailable, UNAVAILABLE},
+		"Gateway Timeout":        {http.StatusGatewayTimeout, DEADLINE_EXCEEDED},
+		"Insufficient Storage":   {http.StatusInsufficientStorage, INTERNAL},
+		"OK":                     {http.StatusOK, UNKNOWN},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := CodeForHTTPStatus(test.input)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestCodeForHTTPStatus_RoundTrip(t *testing.T) {
+	codesMtx.RLock()
+	tt := make(map[ErrorCode]int, len(codes))
+	for code, status := range codes {
+		tt[code] = status
+	}
+	codesMtx.RUnlock()
+
+	for code, status := range tt {
+		t.Run(code.String(), func(t *testing.T) {
+			got := CodeForHTTPStatus(status)
+			if got.HTTPStatus() != status {
+				t.Fatalf("expecting %s to map back to %d, got %s (%d)", code, status, got, got.HTTPStatus())
+			}
+			if back := CodeForHTTPStatus(got.HTTPStatus()); back != got {
+				t.Fatalf("expecting %s, got %s", got, back)
+			}
+		})
+	}
+}
+
+func TestRegisterCode_Preferred(t *testing.T) {
+	tt := map[string]struct {
+		code ErrorCode
+		want ErrorCode
+	}{
+		"Top Level": {"version_conflict", "version_conflict"},
+		"Sub Code":  {"conflict.version", CONFLICT},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			t.Cleanup(func() {
+				codesMtx.Lock()
+				delete(codes, test.code)
+				preferred[http.StatusConflict] = CONFLICT
+				codesMtx.Unlock()
+			})
+			RegisterCode(test.code, http.StatusConflict)
+			got := CodeForHTTPStatus(http.StatusConflict)
+			if got != test.want {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFromHTTPStatus(t *testing.T) {
+	got := FromHTTPStatus(http.StatusTeapot, "I'm a teapot")
+	if got.Code != INVALID || got.Message != "I'm a teapot" {
+		t.Fatalf("unexpected error %+v", got)
+	}
+	if got.Metadata[HTTPStatusKey] != "418" {
+		t.Fatalf("expecting 418, got %s", got.Metadata[HTTPStatusKey])
+	}
+	if !strings.Contains(got.FileLine(), "code_test.go") {
+		t.Fatalf("expecting file line of caller, got %s", got.FileLine())
+	}
+}
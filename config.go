@@ -0,0 +1,128 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"slices"
+	"sync/atomic"
+	"time"
+)
+
+// Config defines the package wide settings, applied with
+// Configure. Zero values are replaced with their defaults.
+type Config struct {
+	// The code used by NewE and ErrorF, defaults to INTERNAL.
+	DefaultCode ErrorCode
+	// The message returned by Message when none has been
+	// found within the chain, defaults to
+	// "An error has occurred."
+	GlobalMessage string
+	// The maximum amount of stack frames captured when
+	// creating an error, defaults to 100.
+	StackDepth int
+	// Determines how file paths are rendered, see PathMode.
+	FilePaths PathMode
+	// The prefixes removed from file paths when FilePaths
+	// is PathTrim.
+	TrimPrefixes []string
+	// The layout used by Error when Formatter is nil.
+	Format FormatOptions
+	// Replaces the layout used by Error entirely when set,
+	// for example with a template.
+	Formatter func(e *Error) string
+	// Determines if errors created by the constructors are
+	// assigned a unique ID, see ID.
+	GenerateIDs bool
+	// Determines if the environment is included when
+	// marshalling errors to JSON, so the deployment can be
+	// identified from stored errors.
+	IncludeEnvironment bool
+	// Returns the current time, used for timestamping errors
+	// when they are created, defaults to time.Now. It may be
+	// replaced to obtain deterministic times in tests.
+	Clock func() time.Time
+}
+
+const (
+	// defaultGlobalMessage is the default value of
+	// Config.GlobalMessage.
+	defaultGlobalMessage = "An error has occurred."
+	// defaultStackDepth is the default value of
+	// Config.StackDepth.
+	defaultStackDepth = 100
+)
+
+var (
+	config        atomic.Pointer[Config]
+	defaultConfig = Config{
+		DefaultCode:   INTERNAL,
+		GlobalMessage: defaultGlobalMessage,
+		StackDepth:    defaultStackDepth,
+	}
+)
+
+// Configure replaces the package wide settings. The config
+// is swapped atomically, so it's safe to call while errors
+// are being created and printed in other goroutines.
+//
+// The deprecated DefaultCode and GlobalError variables take
+// precedence over the config when they've been changed from
+// their defaults.
+func Configure(cfg Config) {
+	if cfg.DefaultCode == "" {
+		cfg.DefaultCode = INTERNAL
+	}
+	if cfg.GlobalMessage == "" {
+		cfg.GlobalMessage = defaultGlobalMessage
+	}
+	if cfg.StackDepth <= 0 {
+		cfg.StackDepth = defaultStackDepth
+	}
+	cfg.TrimPrefixes = slices.Clone(cfg.TrimPrefixes)
+	config.Store(&cfg)
+}
+
+// CurrentConfig returns the settings applied by Configure,
+// or the defaults if it hasn't been called.
+func CurrentConfig() Config {
+	cfg := *loadConfig()
+	cfg.TrimPrefixes = slices.Clone(cfg.TrimPrefixes)
+	return cfg
+}
+
+// loadConfig returns the current config without copying.
+// The returned value must not be modified.
+func loadConfig() *Config {
+	if cfg := config.Load(); cfg != nil {
+		return cfg
+	}
+	return &defaultConfig
+}
+
+// defaultCode returns DefaultCode if it has been changed,
+// otherwise the configured default code.
+func defaultCode() ErrorCode {
+	if DefaultCode != INTERNAL {
+		return DefaultCode
+	}
+	return loadConfig().DefaultCode
+}
+
+// globalMessage returns GlobalError if it has been changed,
+// otherwise the configured global message.
+func globalMessage() string {
+	if GlobalError != defaultGlobalMessage {
+		return GlobalError
+	}
+	return loadConfig().GlobalMessage
+}
+
+// now returns the current time from the configured clock.
+func now() time.Time {
+	if clock := loadConfig().Clock; clock != nil {
+		return clock()
+	}
+	return time.Now()
+}
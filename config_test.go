@@ -0,0 +1,99 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func withConfig(t *testing.T, fn func(cfg *Config)) {
+	t.Helper()
+	orig := CurrentConfig()
+	t.Cleanup(func() { Configure(orig) })
+	cfg := CurrentConfig()
+	fn(&cfg)
+	Configure(cfg)
+}
+
+func TestConfigure(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		withConfig(t, func(cfg *Config) { *cfg = Config{} })
+		got := CurrentConfig()
+		want := Config{DefaultCode: INTERNAL, GlobalMessage: "An error has occurred.", StackDepth: 100}
+		if !reflect.DeepEqual(want, got) {
+			t.Fatalf("expecting %+v, got %+v", want, got)
+		}
+	})
+
+	t.Run("Default Code", func(t *testing.T) {
+		withConfig(t, func(cfg *Config) { cfg.DefaultCode = INVALID })
+		got := NewE(nil, "message", "op").Code
+		if !reflect.DeepEqual(INVALID, got) {
+			t.Fatalf("expecting %s, got %s", INVALID, got)
+		}
+	})
+
+	t.Run("Global Message", func(t *testing.T) {
+		withConfig(t, func(cfg *Config) { cfg.GlobalMessage = "Oops" })
+		got := Message(fmt.Errorf("err"))
+		if !reflect.DeepEqual("Oops", got) {
+			t.Fatalf("expecting %s, got %s", "Oops", got)
+		}
+	})
+
+	t.Run("Stack Depth", func(t *testing.T) {
+		withConfig(t, func(cfg *Config) { cfg.StackDepth = 2 })
+		got := len(NewE(nil, "message", "op").pcs)
+		if !reflect.DeepEqual(2, got) {
+			t.Fatalf("expecting %d, got %d", 2, got)
+		}
+	})
+
+	t.Run("Copies Prefixes", func(t *testing.T) {
+		prefixes := []string{"/a"}
+		withConfig(t, func(cfg *Config) { cfg.FilePaths, cfg.TrimPrefixes = PathTrim, prefixes })
+		prefixes[0] = "/b"
+		got := CurrentConfig().TrimPrefixes
+		if !reflect.DeepEqual([]string{"/a"}, got) {
+			t.Fatalf("expecting %v, got %v", []string{"/a"}, got)
+		}
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		withConfig(t, func(cfg *Config) { *cfg = Config{} })
+		var wg sync.WaitGroup
+		for i := 0; i < 10; i++ {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				Configure(Config{DefaultCode: NOTFOUND, FilePaths: PathBase})
+			}()
+			go func() {
+				defer wg.Done()
+				_ = NewE(fmt.Errorf("err"), "message", "op").Error()
+			}()
+		}
+		wg.Wait()
+	})
+}
+
+func TestConfigure_DeprecatedVars(t *testing.T) {
+	withConfig(t, func(cfg *Config) { cfg.DefaultCode, cfg.GlobalMessage = INVALID, "Oops" })
+
+	origCode, origMessage := DefaultCode, GlobalError
+	t.Cleanup(func() { DefaultCode, GlobalError = origCode, origMessage })
+	DefaultCode, GlobalError = CONFLICT, "Deprecated"
+
+	e := NewE(nil, "message", "op")
+	if got := e.Code; !reflect.DeepEqual(CONFLICT, got) {
+		t.Fatalf("expecting %s, got %s", CONFLICT, got)
+	}
+	if got := Message(fmt.Errorf("err")); !reflect.DeepEqual("Deprecated", got) {
+		t.Fatalf("expecting %s, got %s", "Deprecated", got)
+	}
+}
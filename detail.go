@@ -0,0 +1,138 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	detailsMtx sync.RWMutex
+	// detailTypes maps the registered discriminators to
+	// their detail type.
+	detailTypes = map[string]reflect.Type{}
+	// detailNames maps the registered detail types to their
+	// discriminator.
+	detailNames = map[reflect.Type]string{}
+)
+
+// wrappingDetail is a detail payload along with its type
+// discriminator, suitable for json.Marshal.
+type wrappingDetail struct {
+	Type  string          `json:"type"`
+	Value json.RawMessage `json:"value"`
+}
+
+// RegisterDetail registers the detail type T under the
+// given name, which is used as the type discriminator when
+// marshalling errors to JSON. Details of unregistered types
+// are omitted from JSON. RegisterDetail panics if the name
+// or type has already been registered.
+func RegisterDetail[T any](name string) {
+	typ := reflect.TypeOf((*T)(nil)).Elem()
+	detailsMtx.Lock()
+	defer detailsMtx.Unlock()
+	if _, ok := detailTypes[name]; ok {
+		panic(fmt.Sprintf("errors: detail %q already registered", name))
+	}
+	if _, ok := detailNames[typ]; ok {
+		panic(fmt.Sprintf("errors: detail type %s already registered", typ))
+	}
+	detailTypes[name] = typ
+	detailNames[typ] = name
+}
+
+// WithDetail attaches the structured payload v to the error
+// and returns it, replacing any detail of the same type. If
+// err is not an Error, it's wrapped in one that keeps its
+// code and message. If err is nil, WithDetail returns nil.
+func WithDetail[T any](err error, v T) *Error {
+	if err == nil {
+		return nil
+	}
+	e, ok := err.(*Error)
+	if !ok {
+		e = &Error{Err: err}
+	}
+	for i, d := range e.details {
+		if _, ok := d.(T); ok {
+			e.details[i] = v
+			return e
+		}
+	}
+	e.details = append(e.details, v)
+	return e
+}
+
+// Detail returns the first detail of type T within the
+// tree, in the order errors are visited by Walk.
+func Detail[T any](err error) (T, bool) {
+	var (
+		found T
+		ok    bool
+	)
+	Walk(err, func(err error) bool {
+		e, isErr := err.(*Error)
+		if !isErr {
+			return true
+		}
+		for _, d := range e.details {
+			if found, ok = d.(T); ok {
+				return false
+			}
+		}
+		return true
+	})
+	return found, ok
+}
+
+// marshalDetails returns the registered details of the
+// error along with their type discriminator.
+func (e *Error) marshalDetails() ([]wrappingDetail, error) {
+	if len(e.details) == 0 {
+		return nil, nil
+	}
+	detailsMtx.RLock()
+	defer detailsMtx.RUnlock()
+	var out []wrappingDetail
+	for _, d := range e.details {
+		name, ok := detailNames[reflect.TypeOf(d)]
+		if !ok {
+			continue
+		}
+		buf, err := json.Marshal(d)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, wrappingDetail{Type: name, Value: buf})
+	}
+	return out, nil
+}
+
+// unmarshalDetails decodes the details with a registered
+// type discriminator, unknown types are ignored.
+func unmarshalDetails(details []wrappingDetail) ([]any, error) {
+	if len(details) == 0 {
+		return nil, nil
+	}
+	detailsMtx.RLock()
+	defer detailsMtx.RUnlock()
+	var out []any
+	for _, d := range details {
+		typ, ok := detailTypes[d.Type]
+		if !ok {
+			continue
+		}
+		v := reflect.New(typ)
+		if err := json.Unmarshal(d.Value, v.Interface()); err != nil {
+			return nil, fmt.Errorf("errors: unmarshalling detail %q: %w", d.Type, err)
+		}
+		out = append(out, v.Elem().Interface())
+	}
+	return out, nil
+}
@@ -0,0 +1,179 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type quotaInfo struct {
+	Limit     int `json:"limit"`
+	Remaining int `json:"remaining"`
+}
+
+type conflictingResource struct {
+	ID string `json:"id"`
+}
+
+type unregisteredDetail struct {
+	Value string
+}
+
+func init() {
+	RegisterDetail[quotaInfo]("quota_info")
+	RegisterDetail[conflictingResource]("conflicting_resource")
+}
+
+func TestDetail(t *testing.T) {
+	quota := quotaInfo{Limit: 10, Remaining: 0}
+	resource := conflictingResource{ID: "1"}
+
+	tt := map[string]struct {
+		input error
+		want  any
+	}{
+		"Nil": {
+			nil,
+			nil,
+		},
+		"Missing": {
+			NewInternal(nil, "message", "op"),
+			nil,
+		},
+		"Attached": {
+			WithDetail(NewMaximumAttempts(nil, "message", "op"), quota),
+			quota,
+		},
+		"Multiple Types": {
+			WithDetail(WithDetail(NewConflict(nil, "message", "op"), resource), quota),
+			quota,
+		},
+		"Wrapped": {
+			fmt.Errorf("wrap: %w", NewInternal(WithDetail(NewMaximumAttempts(nil, "message", "op"), quota), "", "")),
+			quota,
+		},
+		"Replaced": {
+			WithDetail(WithDetail(NewMaximumAttempts(nil, "message", "op"), quotaInfo{Limit: 5}), quota),
+			quota,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got, ok := Detail[quotaInfo](test.input)
+			if test.want == nil {
+				if ok {
+					t.Fatalf("expecting no detail, got %+v", got)
+				}
+				return
+			}
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %+v, got %+v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestWithDetail(t *testing.T) {
+	if WithDetail[quotaInfo](nil, quotaInfo{}) != nil {
+		t.Fatal("expecting nil")
+	}
+
+	std := fmt.Errorf("error")
+	e := WithDetail(std, conflictingResource{ID: "1"})
+	if e.Unwrap() != std || Code(e) != INTERNAL || e.Error() != "error" {
+		t.Fatalf("expecting wrapped error to keep its behaviour, got %s", e)
+	}
+	e = WithDetail(e, quotaInfo{Limit: 1})
+	if len(e.details) != 2 {
+		t.Fatalf("expecting 2 details, got %d", len(e.details))
+	}
+	if got, ok := Detail[conflictingResource](e); !ok || got.ID != "1" {
+		t.Fatalf("expecting conflicting resource, got %+v", got)
+	}
+}
+
+func TestRegisterDetail_Panics(t *testing.T) {
+	tt := map[string]func(){
+		"Name": func() { RegisterDetail[unregisteredDetail]("quota_info") },
+		"Type": func() { RegisterDetail[quotaInfo]("quota") },
+	}
+
+	for name, fn := range tt {
+		t.Run(name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Fatal("expecting panic")
+				}
+			}()
+			fn()
+		})
+	}
+}
+
+func TestError_JSONDetails(t *testing.T) {
+	e := NewConflict(nil, "message", "op")
+	WithDetail(e, conflictingResource{ID: "1"})
+	WithDetail(e, quotaInfo{Limit: 10, Remaining: 3})
+	WithDetail(e, unregisteredDetail{Value: "skipped"})
+
+	buf, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	want := `"details":[{"type":"conflicting_resource","value":{"id":"1"}},{"type":"quota_info","value":{"limit":10,"remaining":3}}]`
+	if !strings.Contains(string(buf), want) {
+		t.Fatalf("expecting %s to contain %s", buf, want)
+	}
+
+	got := &Error{}
+	if err = json.Unmarshal(buf, got); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	if quota, ok := Detail[quotaInfo](got); !ok || quota.Remaining != 3 {
+		t.Fatalf("expecting quota info to survive, got %+v", quota)
+	}
+	if resource, ok := Detail[conflictingResource](got); !ok || resource.ID != "1" {
+		t.Fatalf("expecting conflicting resource to survive, got %+v", resource)
+	}
+	if _, ok := Detail[unregisteredDetail](got); ok {
+		t.Fatal("expecting unregistered detail to be omitted")
+	}
+}
+
+func TestError_UnmarshalJSONDetails(t *testing.T) {
+	tt := map[string]struct {
+		input string
+		want  string
+	}{
+		"Unknown Type": {
+			`{"code":"conflict","details":[{"type":"unknown","value":{}}]}`,
+			"",
+		},
+		"Bad Value": {
+			`{"code":"conflict","details":[{"type":"quota_info","value":"bad"}]}`,
+			`unmarshalling detail "quota_info"`,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			err := json.Unmarshal([]byte(test.input), &Error{})
+			if err != nil {
+				if test.want == "" || !strings.Contains(err.Error(), test.want) {
+					t.Fatalf("expecting %s to contain, got %s", test.want, err)
+				}
+				return
+			}
+			if test.want != "" {
+				t.Fatalf("expecting %s, got nil", test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// Environment describes the deployment that produced an
+// error, attached to reported events and optionally to the
+// JSON output of errors.
+type Environment struct {
+	// The name of the service, defaults to the last element
+	// of the main module path.
+	Service string `json:"service,omitempty"`
+	// The version of the main module.
+	Version string `json:"version,omitempty"`
+	// The VCS revision the binary was built from.
+	Revision string `json:"revision,omitempty"`
+	// The Go version the binary was built with.
+	GoVersion string `json:"go_version,omitempty"`
+	// The hostname of the machine.
+	Hostname string `json:"hostname,omitempty"`
+	// The process ID.
+	PID int `json:"pid,omitempty"`
+}
+
+var (
+	environment     atomic.Pointer[Environment]
+	environmentOnce sync.Once
+)
+
+// SetEnvironment sets the environment attached to errors,
+// replacing the detected one. It's intended to be called
+// once on start up, fields left empty are not detected.
+func SetEnvironment(env Environment) {
+	environment.Store(&env)
+}
+
+// CurrentEnvironment returns the environment set with
+// SetEnvironment, or the one obtained by DetectEnvironment
+// if none has been set.
+func CurrentEnvironment() Environment {
+	environmentOnce.Do(func() {
+		if environment.Load() == nil {
+			env := DetectEnvironment()
+			environment.CompareAndSwap(nil, &env)
+		}
+	})
+	return *environment.Load()
+}
+
+// DetectEnvironment returns the environment of the running
+// process, obtained from the build information embedded in
+// the binary, the hostname and the process ID.
+func DetectEnvironment() Environment {
+	env := Environment{
+		GoVersion: runtime.Version(),
+		PID:       os.Getpid(),
+	}
+	env.Hostname, _ = os.Hostname()
+	if len(os.Args) > 0 {
+		env.Service = filepath.Base(os.Args[0])
+	}
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return env
+	}
+	if info.GoVersion != "" {
+		env.GoVersion = info.GoVersion
+	}
+	if info.Main.Path != "" {
+		env.Service = path.Base(info.Main.Path)
+	}
+	if v := info.Main.Version; v != "" && v != "(devel)" {
+		env.Version = v
+	}
+	for _, s := range info.Settings {
+		if s.Key == "vcs.revision" {
+			env.Revision = s.Value
+		}
+	}
+	return env
+}
+
+// Environment returns the environment the error was
+// marshalled with, when restored from JSON, for example
+// by Scan. Errors created within the process return false.
+func (e *Error) Environment() (Environment, bool) {
+	if e.env == nil {
+		return Environment{}, false
+	}
+	return *e.env, true
+}
@@ -0,0 +1,119 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func setEnvironment(t *testing.T, env Environment) {
+	t.Helper()
+	CurrentEnvironment()
+	orig := environment.Load()
+	t.Cleanup(func() { environment.Store(orig) })
+	SetEnvironment(env)
+}
+
+func TestDetectEnvironment(t *testing.T) {
+	got := DetectEnvironment()
+	if got.PID != os.Getpid() {
+		t.Fatalf("expecting %d, got %d", os.Getpid(), got.PID)
+	}
+	if got.GoVersion != runtime.Version() {
+		t.Fatalf("expecting %s, got %s", runtime.Version(), got.GoVersion)
+	}
+	if got.Service == "" {
+		t.Fatal("expecting service to be detected")
+	}
+	if hostname, _ := os.Hostname(); got.Hostname != hostname {
+		t.Fatalf("expecting %s, got %s", hostname, got.Hostname)
+	}
+}
+
+func TestSetEnvironment(t *testing.T) {
+	want := Environment{Service: "api", Version: "v1.0.0"}
+	setEnvironment(t, want)
+	if got := CurrentEnvironment(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expecting %+v, got %+v", want, got)
+	}
+}
+
+func TestError_JSONEnvironment(t *testing.T) {
+	env := Environment{Service: "api", Version: "v1.0.0", Revision: "abc123"}
+	setEnvironment(t, env)
+
+	tt := map[string]struct {
+		include bool
+		want    bool
+	}{
+		"Included": {true, true},
+		"Excluded": {false, false},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			withConfig(t, func(cfg *Config) { cfg.IncludeEnvironment = test.include })
+
+			buf, err := json.Marshal(NewInternal(nil, "message", "op"))
+			if err != nil {
+				t.Fatalf("expecting nil, got %s", err)
+			}
+			if got := strings.Contains(string(buf), `"environment":{"service":"api","version":"v1.0.0","revision":"abc123"}`); got != test.want {
+				t.Fatalf("expecting environment %t, got %s", test.want, buf)
+			}
+
+			var e Error
+			if err = e.Scan(buf); err != nil {
+				t.Fatalf("expecting nil, got %s", err)
+			}
+			got, ok := e.Environment()
+			if ok != test.want {
+				t.Fatalf("expecting %t, got %t", test.want, ok)
+			}
+			if ok && !reflect.DeepEqual(env, got) {
+				t.Fatalf("expecting %+v, got %+v", env, got)
+			}
+		})
+	}
+}
+
+func TestNewEvent_Environment(t *testing.T) {
+	current := Environment{Service: "api", Version: "v2.0.0"}
+	setEnvironment(t, current)
+
+	if got := NewEvent(NewInternal(nil, "message", "op")).Environment; !reflect.DeepEqual(current, got) {
+		t.Fatalf("expecting %+v, got %+v", current, got)
+	}
+
+	stored := &Error{}
+	if err := json.Unmarshal([]byte(`{"code":"internal","environment":{"service":"worker","version":"v1.0.0"}}`), stored); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	want := Environment{Service: "worker", Version: "v1.0.0"}
+	if got := NewEvent(stored).Environment; !reflect.DeepEqual(want, got) {
+		t.Fatalf("expecting %+v, got %+v", want, got)
+	}
+}
+
+func TestSentryReporter_Environment(t *testing.T) {
+	setEnvironment(t, Environment{Service: "api", Version: "v1.0.0", Revision: "abc123", Hostname: "host"})
+	r, err := NewSentryReporter(SentryOptions{DSN: "https://key@sentry.example.com/42"})
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+
+	got := r.sentryEvent(NewEvent(NewInternal(nil, "message", "op")))
+	if got.Release != "v1.0.0" || got.ServerName != "host" {
+		t.Fatalf("expecting release and server name from environment, got %+v", got)
+	}
+	if got.Tags["service"] != "api" || got.Tags["revision"] != "abc123" {
+		t.Fatalf("expecting environment tags, got %v", got.Tags)
+	}
+}
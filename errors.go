@@ -6,106 +6,192 @@
 package errors
 
 import (
-	"bytes"
 	"database/sql/driver"
 	"encoding/json"
 	"errors"
 	"fmt"
-	"net/http"
+	"io"
 	"runtime"
 	"strconv"
 	"strings"
+	"time"
 )
 
-// Application error codes.
+// Application error codes. CANCELLED onwards follow the
+// canonical gRPC codes described in Google's AIP-193. The
+// original codes correspond to the canonical set as follows:
+//
+//	CONFLICT        - ABORTED or ALREADY_EXISTS
+//	INTERNAL        - INTERNAL
+//	INVALID         - INVALID_ARGUMENT
+//	NOTFOUND        - NOT_FOUND
+//	UNKNOWN         - UNKNOWN
+//	MAXIMUMATTEMPTS - RESOURCE_EXHAUSTED
+//	EXPIRED         - FAILED_PRECONDITION
 const (
 	// CONFLICT - An action cannot be performed.
-	CONFLICT = "conflict"
+	CONFLICT ErrorCode = "conflict"
 	// INTERNAL - Error within the application.
-	INTERNAL = "internal"
+	INTERNAL ErrorCode = "internal"
 	// INVALID - Validation failed.
-	INVALID = "invalid"
+	INVALID ErrorCode = "invalid"
 	// NOTFOUND - Entity does not exist.
-	NOTFOUND = "not_found"
+	NOTFOUND ErrorCode = "not_found"
 	// UNKNOWN - Application unknown error.
-	UNKNOWN = "unknown"
+	UNKNOWN ErrorCode = "unknown"
 	// MAXIMUMATTEMPTS - More than allowed action.
-	MAXIMUMATTEMPTS = "maximum_attempts"
+	MAXIMUMATTEMPTS ErrorCode = "maximum_attempts"
 	// EXPIRED - Subscription expired.
-	EXPIRED = "expired"
+	EXPIRED ErrorCode = "expired"
+	// CANCELLED - The operation was cancelled by the caller.
+	CANCELLED ErrorCode = "cancelled"
+	// DEADLINE_EXCEEDED - The operation timed out.
+	DEADLINE_EXCEEDED ErrorCode = "deadline_exceeded" //nolint:revive // Matches gRPC naming.
+	// UNAUTHENTICATED - The request does not have valid authentication credentials.
+	UNAUTHENTICATED ErrorCode = "unauthenticated"
+	// PERMISSION_DENIED - The caller does not have permission to perform the action.
+	PERMISSION_DENIED ErrorCode = "permission_denied" //nolint:revive // Matches gRPC naming.
+	// UNAVAILABLE - The service is currently unavailable, the caller may retry.
+	UNAVAILABLE ErrorCode = "unavailable"
+	// UNIMPLEMENTED - The operation is not implemented or supported.
+	UNIMPLEMENTED ErrorCode = "unimplemented"
+	// FAILED_PRECONDITION - The system is not in a state required for the operation.
+	FAILED_PRECONDITION ErrorCode = "failed_precondition" //nolint:revive // Matches gRPC naming.
+	// ALREADY_EXISTS - The entity the caller attempted to create already exists.
+	ALREADY_EXISTS ErrorCode = "already_exists" //nolint:revive // Matches gRPC naming.
+	// ABORTED - The operation was aborted due to a concurrency conflict.
+	ABORTED ErrorCode = "aborted"
+	// OUT_OF_RANGE - The operation was attempted past the valid range.
+	OUT_OF_RANGE ErrorCode = "out_of_range" //nolint:revive // Matches gRPC naming.
+	// DATA_LOSS - Unrecoverable data loss or corruption.
+	DATA_LOSS ErrorCode = "data_loss" //nolint:revive // Matches gRPC naming.
+	// RESOURCE_EXHAUSTED - A quota or resource has been exhausted.
+	RESOURCE_EXHAUSTED ErrorCode = "resource_exhausted" //nolint:revive // Matches gRPC naming.
+)
+
+// Sub-codes for HTTP statuses without a code of their own,
+// so the mapping from HTTP statuses round trips. Each one
+// inherits the behaviour of its parent code.
+const (
+	// METHODNOTALLOWED - The method is not supported by the resource.
+	METHODNOTALLOWED ErrorCode = "invalid.method_not_allowed"
+	// REQUESTTIMEOUT - The request was not received in time.
+	REQUESTTIMEOUT ErrorCode = "deadline_exceeded.request_timeout"
+	// GONE - The entity no longer exists.
+	GONE ErrorCode = "not_found.gone"
+	// PRECONDITIONFAILED - A precondition of the request was not met.
+	PRECONDITIONFAILED ErrorCode = "failed_precondition.conditional"
+	// TOOLARGE - The request is larger than allowed.
+	TOOLARGE ErrorCode = "invalid.too_large"
+	// UNSUPPORTEDMEDIATYPE - The format of the request is not supported.
+	UNSUPPORTEDMEDIATYPE ErrorCode = "invalid.unsupported_media_type"
+	// UNPROCESSABLE - The request is well-formed but semantically invalid.
+	UNPROCESSABLE ErrorCode = "invalid.unprocessable"
+	// BADGATEWAY - An upstream service returned an invalid response.
+	BADGATEWAY ErrorCode = "unavailable.bad_gateway"
 )
 
 var (
 	// DefaultCode is the default code returned when
 	// none is specified.
+	//
+	// Deprecated: Use Config.DefaultCode with Configure.
 	DefaultCode = INTERNAL
 	// GlobalError is a general message when no error message
 	// has been found.
-	GlobalError = "An error has occurred."
+	//
+	// Deprecated: Use Config.GlobalMessage with Configure.
+	GlobalError = defaultGlobalMessage
 )
 
 // Error defines a standard application error.
 type Error struct {
 	// The application error code.
-	Code string `json:"code" bson:"code"`
+	Code ErrorCode `json:"code" bson:"code"`
 	// A human-readable message to send back to the end user.
 	Message string `json:"message" bson:"message"`
 	// Defines what operation is currently being run.
 	Operation string `json:"operation" bson:"op"`
 	// The error that was returned from the caller.
-	Err      error `json:"error" bson:"error"`
+	Err error `json:"error" bson:"error"`
+	// Additional key value pairs describing the error, such
+	// as an ID or a table name.
+	Metadata map[string]string `json:"metadata" bson:"metadata"`
 	fileLine string
+	id       string
+	time     time.Time
+	elapsed  time.Duration
+	env      *Environment
 	pcs      []uintptr
+	stack    []Frame
+	details  []any
+	severity Severity
+	sentinel *Error
 }
 
 // Error returns the string representation of the error
 // message by implementing the error interface.
 func (e *Error) Error() string {
-	var buf bytes.Buffer
-
-	// Print the error code if there is one.
-	if e.Code != "" {
-		buf.WriteString("<" + e.Code + "> ")
-	}
-
-	// Print the file-line, if any.
-	if e.fileLine != "" {
-		buf.WriteString(e.fileLine + " - ")
-	}
-
-	// Print the current operation in our stack, if any.
-	if e.Operation != "" {
-		buf.WriteString(e.Operation + ": ")
-	}
-
-	// Print the original error message, if any.
-	if e.Err != nil {
-		buf.WriteString(e.Err.Error() + ", ")
+	cfg := loadConfig()
+	if cfg.Formatter != nil {
+		return cfg.Formatter(e)
 	}
+	// Wrapped errors use the same config, so they can
+	// print themselves.
+	return cfg.Format.format(e, false)
+}
 
-	// Print the message, if any.
-	if e.Message != "" {
-		buf.WriteString(e.Message)
+// Format implements fmt.Formatter. The %+v verb prints
+// the error followed by its stacktrace, all other verbs
+// print the error as returned by Error.
+func (e *Error) Format(s fmt.State, verb rune) {
+	switch {
+	case verb == 'v' && s.Flag('+'):
+		io.WriteString(s, e.Error()) //nolint:errcheck
+		if len(e.pcs) > 0 {
+			io.WriteString(s, "\n"+e.StackTrace()) //nolint:errcheck
+		}
+	case verb == 'q':
+		fmt.Fprintf(s, "%q", e.Error())
+	default:
+		io.WriteString(s, e.Error()) //nolint:errcheck
 	}
-
-	return strings.TrimSuffix(strings.TrimSpace(buf.String()), ",")
 }
 
-// NewE returns an Error with the DefaultCode.
+// NewE returns an Error with the configured default code.
 func NewE(err error, message, op string) *Error {
-	return newError(err, message, DefaultCode, op)
+	return newError(err, message, defaultCode(), op)
 }
 
-// ErrorF returns an Error with the DefaultCode and
+// ErrorF returns an Error with the default code and
 // formatted message arguments.
 func ErrorF(err error, op, format string, args ...any) *Error {
 	return NewE(err, fmt.Sprintf(format, args...), op)
 }
 
 // FileLine returns the file and line in which the error
-// occurred.
+// occurred, rendered according to Config.FilePaths.
 func (e *Error) FileLine() string {
-	return e.fileLine
+	return renderFileLine(e.fileLine, e.pcs)
+}
+
+// WithFileLine sets the file and line in which the error
+// occurred and returns the error to allow for chaining.
+// It's intended for restoring errors decoded from other
+// formats.
+func (e *Error) WithFileLine(fileLine string) *Error {
+	e.fileLine = fileLine
+	return e
+}
+
+// WithMeta attaches a key value pair to the error's metadata
+// and returns the error to allow for chaining.
+func (e *Error) WithMeta(key, value string) *Error {
+	if e.Metadata == nil {
+		e.Metadata = make(map[string]string)
+	}
+	e.Metadata[key] = value
+	return e
 }
 
 // Unwrap unwraps the original error message.
@@ -113,6 +199,14 @@ func (e *Error) Unwrap() error {
 	return e.Err
 }
 
+// Is reports whether the error was created from the target
+// sentinel by calling With, allowing errors.Is to compare
+// against sentinels returned from Define.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && e.sentinel != nil && e.sentinel == t
+}
+
 // Wrap returns an error annotating err with a stack trace
 // at the point Wrap is called, and the supplied message.
 // If err is nil, Wrap returns nil.
@@ -129,20 +223,7 @@ func Wrap(err error, message string) *Error {
 // HTTPStatusCode is a convenience method used to get the appropriate
 // HTTP response status code for the respective error type.
 func (e *Error) HTTPStatusCode() int {
-	status := http.StatusInternalServerError
-	switch e.Code {
-	case CONFLICT:
-		return http.StatusConflict
-	case INVALID:
-		return http.StatusBadRequest
-	case NOTFOUND:
-		return http.StatusNotFound
-	case EXPIRED:
-		return http.StatusPaymentRequired
-	case MAXIMUMATTEMPTS:
-		return http.StatusTooManyRequests
-	}
-	return status
+	return e.Code.HTTPStatus()
 }
 
 // RuntimeFrames returns function/file/line information.
@@ -163,11 +244,10 @@ func (e *Error) StackTrace() string {
 	trace := make([]string, 0, 100)
 	rFrames := e.RuntimeFrames()
 	frame, ok := rFrames.Next()
-	line := strconv.Itoa(frame.Line)
 	trace = append(trace, frame.Function+"(): "+e.Message)
 
 	for ok {
-		trace = append(trace, "\t"+frame.File+":"+line)
+		trace = append(trace, "\t"+renderPath(frame.File, frame.Function)+":"+strconv.Itoa(frame.Line))
 		frame, ok = rFrames.Next()
 	}
 
@@ -180,11 +260,10 @@ func (e *Error) StackTraceSlice() []string {
 	trace := make([]string, 0, 100)
 	rFrames := e.RuntimeFrames()
 	frame, ok := rFrames.Next()
-	line := strconv.Itoa(frame.Line)
 	trace = append(trace, frame.Function+"(): "+e.Message)
 
 	for ok {
-		trace = append(trace, frame.File+":"+line)
+		trace = append(trace, renderPath(frame.File, frame.Function)+":"+strconv.Itoa(frame.Line))
 		frame, ok = rFrames.Next()
 	}
 
@@ -194,25 +273,50 @@ func (e *Error) StackTraceSlice() []string {
 // wrappingError is the wrapping error features the error
 // and file line in strings suitable for json.Marshal.
 type wrappingError struct {
-	Code      string `json:"code"`
-	Message   string `json:"message"`
-	Operation string `json:"operation"`
-	Err       string `json:"error"`
-	FileLine  string `json:"file_line"`
+	ID        string            `json:"id,omitempty"`
+	Code      string            `json:"code"`
+	Category  string            `json:"category,omitempty"`
+	Message   string            `json:"message"`
+	Operation string            `json:"operation"`
+	Err       string            `json:"error"`
+	FileLine  string            `json:"file_line"`
+	Metadata  map[string]string `json:"metadata,omitempty"`
+	Details   []wrappingDetail  `json:"details,omitempty"`
+	Time      string            `json:"time,omitempty"`
+	Elapsed   string            `json:"elapsed,omitempty"`
+	Env       *Environment      `json:"environment,omitempty"`
 }
 
 // MarshalJSON implements encoding/Marshaller to wrap the
 // error as a string if there is one.
 func (e *Error) MarshalJSON() ([]byte, error) {
 	err := wrappingError{
-		Code:      e.Code,
+		ID:        e.id,
+		Code:      string(e.Code),
+		Category:  string(e.Code.Category()),
 		Message:   e.Message,
 		Operation: e.Operation,
+		Metadata:  e.Metadata,
 	}
 	if e.Err != nil {
 		err.Err = e.Err.Error()
-		err.FileLine = e.fileLine
+		err.FileLine = e.FileLine()
+	}
+	if !e.time.IsZero() {
+		err.Time = e.time.UTC().Format(time.RFC3339Nano)
 	}
+	if e.elapsed != 0 {
+		err.Elapsed = e.elapsed.String()
+	}
+	if err.Env = e.env; err.Env == nil && loadConfig().IncludeEnvironment {
+		env := CurrentEnvironment()
+		err.Env = &env
+	}
+	details, mErr := e.marshalDetails()
+	if mErr != nil {
+		return nil, mErr
+	}
+	err.Details = details
 	return json.Marshal(err)
 }
 
@@ -224,14 +328,28 @@ func (e *Error) UnmarshalJSON(data []byte) error {
 	if mErr != nil {
 		return mErr
 	}
-	e.Code = err.Code
+	e.id = err.ID
+	e.Code = ErrorCode(err.Code)
 	e.Message = err.Message
 	e.Operation = err.Operation
 	e.fileLine = err.FileLine
+	e.Metadata = err.Metadata
 	if err.Err != "" {
 		e.Err = errors.New(err.Err)
 	}
-	return nil
+	e.time, e.elapsed, e.env = time.Time{}, 0, err.Env
+	if err.Time != "" {
+		if e.time, mErr = time.Parse(time.RFC3339Nano, err.Time); mErr != nil {
+			return mErr
+		}
+	}
+	if err.Elapsed != "" {
+		if e.elapsed, mErr = time.ParseDuration(err.Elapsed); mErr != nil {
+			return mErr
+		}
+	}
+	e.details, mErr = unmarshalDetails(err.Details)
+	return mErr
 }
 
 // Scan implements the sql.Scanner interface.
@@ -11,47 +11,56 @@ import (
 
 func BenchmarkNew(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		_ = NewE(errors.New("error"), "message", INTERNAL)
+		_ = NewE(errors.New("error"), "message", "op")
 	}
 }
 
 func BenchmarkNewInternal(b *testing.B) {
 	for i := 0; i < b.N; i++ {
-		_ = NewE(errors.New("error"), "message", INTERNAL)
+		_ = NewE(errors.New("error"), "message", "op")
 	}
 }
 
 func BenchmarkError_Error(b *testing.B) {
-	e := NewE(errors.New("error"), "message", INTERNAL)
+	e := NewE(errors.New("error"), "message", "op")
 	for i := 0; i < b.N; i++ {
 		_ = e.Error()
 	}
 }
 
 func BenchmarkError_Code(b *testing.B) {
-	e := NewE(errors.New("error"), "message", INTERNAL)
+	e := NewE(errors.New("error"), "message", "op")
 	for i := 0; i < b.N; i++ {
 		_ = Code(e)
 	}
 }
 
 func BenchmarkError_Message(b *testing.B) {
-	e := NewE(errors.New("error"), "message", INTERNAL)
+	e := NewE(errors.New("error"), "message", "op")
 	for i := 0; i < b.N; i++ {
 		_ = Message(e)
 	}
 }
 
 func BenchmarkError_ToError(b *testing.B) {
-	e := NewE(errors.New("error"), "message", INTERNAL)
+	e := NewE(errors.New("error"), "message", "op")
 	for i := 0; i < b.N; i++ {
 		_ = ToError(e)
 	}
 }
 
 func BenchmarkError_HTTPStatusCode(b *testing.B) {
-	e := NewE(errors.New("error"), "message", INTERNAL)
+	e := NewE(errors.New("error"), "message", "op")
 	for i := 0; i < b.N; i++ {
 		_ = e.HTTPStatusCode()
 	}
 }
+
+func BenchmarkFormatWith(b *testing.B) {
+	e := NewE(errors.New("error"), "message", "op")
+	opts := FormatOptions{OmitFileLine: true, MessageFirst: true}
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		_ = FormatWith(e, opts)
+	}
+}
@@ -103,6 +103,14 @@ func TestError_FileLine(t *testing.T) {
 	}
 }
 
+func TestError_WithMeta(t *testing.T) {
+	got := NewInternal(nil, "message", "op").WithMeta("key", "value")
+	want := map[string]string{"key": "value"}
+	if !reflect.DeepEqual(want, got.Metadata) {
+		t.Fatalf("expecting %v, got %v", want, got.Metadata)
+	}
+}
+
 func TestWrap(t *testing.T) {
 	got := Wrap(fmt.Errorf("error"), "message")
 	if !reflect.DeepEqual("message", got.Message) {
@@ -154,6 +162,58 @@ func TestError_HTTPStatusCode(t *testing.T) {
 			Error{Code: EXPIRED},
 			http.StatusPaymentRequired,
 		},
+		"Cancelled": {
+			Error{Code: CANCELLED},
+			StatusClientClosedRequest,
+		},
+		"Deadline Exceeded": {
+			Error{Code: DEADLINE_EXCEEDED},
+			http.StatusGatewayTimeout,
+		},
+		"Unauthenticated": {
+			Error{Code: UNAUTHENTICATED},
+			http.StatusUnauthorized,
+		},
+		"Permission Denied": {
+			Error{Code: PERMISSION_DENIED},
+			http.StatusForbidden,
+		},
+		"Unavailable": {
+			Error{Code: UNAVAILABLE},
+			http.StatusServiceUnavailable,
+		},
+		"Unimplemented": {
+			Error{Code: UNIMPLEMENTED},
+			http.StatusNotImplemented,
+		},
+		"Failed Precondition": {
+			Error{Code: FAILED_PRECONDITION},
+			http.StatusBadRequest,
+		},
+		"Already Exists": {
+			Error{Code: ALREADY_EXISTS},
+			http.StatusConflict,
+		},
+		"Aborted": {
+			Error{Code: ABORTED},
+			http.StatusConflict,
+		},
+		"Out Of Range": {
+			Error{Code: OUT_OF_RANGE},
+			http.StatusBadRequest,
+		},
+		"Data Loss": {
+			Error{Code: DATA_LOSS},
+			http.StatusInternalServerError,
+		},
+		"Resource Exhausted": {
+			Error{Code: RESOURCE_EXHAUSTED},
+			http.StatusTooManyRequests,
+		},
+		"Wrapped Code": {
+			Error{Err: NewNotFound(nil, "", "")},
+			http.StatusInternalServerError,
+		},
 	}
 
 	for name, test := range tt {
@@ -210,11 +270,19 @@ func TestError_MarshalJSON(t *testing.T) {
 	}{
 		"With Error": {
 			NewInternal(errors.New("error"), "message", "op"),
-			`{"code":"internal","message":"message","operation":"op","error":"error"`,
+			`{"code":"internal","category":"internal","message":"message","operation":"op","error":"error"`,
 		},
 		"No Error": {
 			NewInternal(nil, "message", "op"),
-			`{"code":"internal","message":"message","operation":"op","error":""`,
+			`{"code":"internal","category":"internal","message":"message","operation":"op","error":""`,
+		},
+		"Hierarchical Code": {
+			&Error{Code: "not_found.user"},
+			`{"code":"not_found.user","category":"not_found"`,
+		},
+		"With Metadata": {
+			NewInternal(nil, "message", "op").WithMeta("key", "value"),
+			`"metadata":{"key":"value"}`,
 		},
 	}
 
@@ -326,3 +394,52 @@ func TestError_Value(t *testing.T) {
 		}
 	})
 }
+
+func TestError_Format(t *testing.T) {
+	err := NewInternal(errors.New("error"), "message", "op")
+
+	tt := map[string]struct {
+		format string
+		want   string
+	}{
+		"String": {"%s", err.Error()},
+		"Value":  {"%v", err.Error()},
+		"Quoted": {"%q", fmt.Sprintf("%q", err.Error())},
+		"Plus":   {"%+v", err.Error() + "\ngithub.com/ainsleyclark/errors.TestError_Format(): message"},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := fmt.Sprintf(test.format, err)
+			if !strings.HasPrefix(got, test.want) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestError_WithFileLine(t *testing.T) {
+	got := NewInternal(nil, "message", "op").WithFileLine("main.go:10").FileLine()
+	if !reflect.DeepEqual("main.go:10", got) {
+		t.Fatalf("expecting main.go:10, got %s", got)
+	}
+}
+
+func TestError_StackTraceLines(t *testing.T) {
+	e := func() *Error { return NewE(fmt.Errorf("error"), "message", "op") }()
+	frames := e.Frames()
+	if len(frames) < 3 {
+		t.Fatalf("expecting multiple frames, got %v", frames)
+	}
+
+	got := e.StackTraceSlice()[1:]
+	for i, line := range got {
+		want := ":" + fmt.Sprint(frames[i].Line)
+		if !strings.HasSuffix(line, want) {
+			t.Fatalf("expecting %s to end with %s", line, want)
+		}
+	}
+	if trace := e.StackTrace(); !strings.Contains(trace, strings.Join(got, "\n\t")) {
+		t.Fatalf("expecting %s to contain %v", trace, got)
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package errorspb provides the Protocol Buffers definition
+// of an application error along with conversions to and
+// from errors.Error, so errors can be embedded in any
+// protobuf API response.
+package errorspb
+
+//go:generate protoc --proto_path=proto --go_out=. --go_opt=module=github.com/ainsleyclark/errors/errorspb ainsleyclark/errors/v1/errors.proto
+
+import (
+	"github.com/ainsleyclark/errors"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// ToProto converts the error to its protobuf representation.
+// Errors wrapped by e are flattened into causes, from the
+// immediate cause to the root cause. If e is nil, ToProto
+// returns nil.
+func ToProto(e *errors.Error) *Error {
+	if e == nil {
+		return nil
+	}
+	p := toProto(e)
+	p.Id = errors.ID(e)
+	last := p
+	for err := e.Err; err != nil; {
+		wrapped, ok := err.(*errors.Error)
+		if !ok {
+			last.Error = err.Error()
+			break
+		}
+		last = toProto(wrapped)
+		p.Causes = append(p.Causes, last)
+		err = wrapped.Err
+	}
+	return p
+}
+
+// FromProto converts the protobuf representation back to an
+// error, rebuilding the wrapped chain from the causes. The ID
+// is restored on every error within the chain. If p is nil,
+// FromProto returns nil.
+func FromProto(p *Error) *errors.Error {
+	if p == nil {
+		return nil
+	}
+	e := fromProto(p).WithID(p.GetId())
+	last := e
+	for _, cause := range p.Causes {
+		c := fromProto(cause).WithID(p.GetId())
+		last.Err = c
+		last = c
+	}
+	if text := innermost(p).GetError(); text != "" {
+		last.Err = errors.New(text)
+	}
+	return e
+}
+
+// toProto converts the fields of a single error.
+func toProto(e *errors.Error) *Error {
+	p := &Error{
+		Code:      e.Code.String(),
+		Message:   e.Message,
+		Operation: e.Operation,
+		FileLine:  e.FileLine(),
+	}
+	if !e.Time().IsZero() {
+		p.Time = timestamppb.New(e.Time())
+	}
+	if len(e.Metadata) > 0 {
+		p.Metadata = make(map[string]string, len(e.Metadata))
+		for k, v := range e.Metadata {
+			p.Metadata[k] = v
+		}
+	}
+	for _, f := range e.Frames() {
+		p.Frames = append(p.Frames, &Frame{
+			Function: f.Function,
+			File:     f.File,
+			Line:     int64(f.Line),
+		})
+	}
+	return p
+}
+
+// fromProto converts the fields of a single error.
+func fromProto(p *Error) *errors.Error {
+	e := &errors.Error{
+		Code:      errors.ErrorCode(p.GetCode()),
+		Message:   p.GetMessage(),
+		Operation: p.GetOperation(),
+	}
+	if len(p.GetMetadata()) > 0 {
+		e.Metadata = make(map[string]string, len(p.GetMetadata()))
+		for k, v := range p.GetMetadata() {
+			e.Metadata[k] = v
+		}
+	}
+	if p.GetTime() != nil {
+		e.WithTime(p.GetTime().AsTime())
+	}
+	var frames []errors.Frame
+	for _, f := range p.GetFrames() {
+		frames = append(frames, errors.Frame{
+			Function: f.GetFunction(),
+			File:     f.GetFile(),
+			Line:     int(f.GetLine()),
+		})
+	}
+	return e.WithFileLine(p.GetFileLine()).WithFrames(frames)
+}
+
+// innermost returns the last cause of p, or p itself if it
+// has no causes.
+func innermost(p *Error) *Error {
+	if n := len(p.GetCauses()); n > 0 {
+		return p.GetCauses()[n-1]
+	}
+	return p
+}
@@ -0,0 +1,125 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errorspb
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/ainsleyclark/errors"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func TestToProto(t *testing.T) {
+	tt := map[string]struct {
+		input *errors.Error
+		want  *Error
+	}{
+		"Nil": {
+			nil,
+			nil,
+		},
+		"Fields": {
+			(&errors.Error{Code: errors.NOTFOUND, Message: "message", Operation: "op"}).WithMeta("id", "1"),
+			&Error{Code: "not_found", Message: "message", Operation: "op", Metadata: map[string]string{"id": "1"}},
+		},
+		"Chain": {
+			&errors.Error{Code: errors.INTERNAL, Operation: "outer", Err: &errors.Error{Code: errors.NOTFOUND, Err: fmt.Errorf("sql: no rows")}},
+			&Error{Code: "internal", Operation: "outer", Causes: []*Error{{Code: "not_found", Error: "sql: no rows"}}},
+		},
+		"Standard Error": {
+			&errors.Error{Code: errors.INTERNAL, Err: fmt.Errorf("error")},
+			&Error{Code: "internal", Error: "error"},
+		},
+		"Identity": {
+			(&errors.Error{Code: errors.INTERNAL}).WithID("id").WithTime(time.Unix(1, 0)),
+			&Error{Code: "internal", Id: "id", Time: timestamppb.New(time.Unix(1, 0))},
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := ToProto(test.input)
+			if !proto.Equal(test.want, got) {
+				t.Fatalf("expecting %v, got %v", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFromProto(t *testing.T) {
+	if FromProto(nil) != nil {
+		t.Fatal("expecting nil")
+	}
+
+	input := &Error{
+		Code:      "internal",
+		Operation: "outer",
+		FileLine:  "main.go:10",
+		Frames:    []*Frame{{Function: "main.main", File: "main.go", Line: 10}},
+		Causes:    []*Error{{Code: "not_found", Message: "User not found", Error: "sql: no rows"}},
+	}
+	got := FromProto(input)
+
+	if got.Code != errors.INTERNAL || got.Operation != "outer" || got.FileLine() != "main.go:10" {
+		t.Fatalf("unexpected error %+v", got)
+	}
+	want := []errors.Frame{{Function: "main.main", File: "main.go", Line: 10}}
+	if !reflect.DeepEqual(want, got.Frames()) {
+		t.Fatalf("expecting %v, got %v", want, got.Frames())
+	}
+	if errors.Message(got) != "User not found" || !errors.HasCode(got.Err, errors.NOTFOUND) {
+		t.Fatalf("expecting cause to be restored, got %v", got.Err)
+	}
+	if root := errors.Unwrap(got.Err); root == nil || root.Error() != "sql: no rows" {
+		t.Fatalf("expecting root error, got %v", root)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	want := errors.NewInternal(errors.NewNotFound(fmt.Errorf("sql: no rows"), "User not found", "UserStore.Find"), "", "Handler").
+		WithMeta("id", "1").
+		WithID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+
+	buf, err := proto.Marshal(ToProto(want))
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	p := &Error{}
+	if err = proto.Unmarshal(buf, p); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	got := FromProto(p)
+
+	if !reflect.DeepEqual(want.Error(), got.Error()) {
+		t.Fatalf("expecting %s, got %s", want.Error(), got.Error())
+	}
+	if !reflect.DeepEqual(want.Frames(), got.Frames()) {
+		t.Fatalf("expecting %v, got %v", want.Frames(), got.Frames())
+	}
+	if !reflect.DeepEqual(errors.Metadata(want), errors.Metadata(got)) {
+		t.Fatalf("expecting %v, got %v", errors.Metadata(want), errors.Metadata(got))
+	}
+	if errors.ID(want) != errors.ID(got) || errors.ID(want) != errors.ID(got.Err) {
+		t.Fatalf("expecting %s, got %s", errors.ID(want), errors.ID(got))
+	}
+	if !want.Time().Equal(got.Time()) {
+		t.Fatalf("expecting %s, got %s", want.Time(), got.Time())
+	}
+	wantCause, gotCause := want.Err.(*errors.Error), got.Err.(*errors.Error)
+	if !wantCause.Time().Equal(gotCause.Time()) {
+		t.Fatalf("expecting %s, got %s", wantCause.Time(), gotCause.Time())
+	}
+}
+
+func TestDescriptor_Path(t *testing.T) {
+	want := "ainsleyclark/errors/v1/errors.proto"
+	if got := File_ainsleyclark_errors_v1_errors_proto.Path(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,300 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: ainsleyclark/errors/v1/errors.proto
+
+package errorspb
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Error defines a standard application error.
+type Error struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// The application error code, such as "not_found".
+	Code string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	// A human-readable message to send back to the end user.
+	Message string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	// Defines what operation is currently being run.
+	Operation string `protobuf:"bytes,3,opt,name=operation,proto3" json:"operation,omitempty"`
+	// The file and line in which the error occurred.
+	FileLine string `protobuf:"bytes,4,opt,name=file_line,json=fileLine,proto3" json:"file_line,omitempty"`
+	// Additional key value pairs describing the error.
+	Metadata map[string]string `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	// The errors wrapped by this error, from the immediate
+	// cause to the root cause. Causes do not have causes of
+	// their own.
+	Causes []*Error `protobuf:"bytes,6,rep,name=causes,proto3" json:"causes,omitempty"`
+	// The stacktrace of the error, with the most recent call
+	// first.
+	Frames []*Frame `protobuf:"bytes,7,rep,name=frames,proto3" json:"frames,omitempty"`
+	// The text of a wrapped error that is not an application
+	// error, set on the innermost error within the chain.
+	Error string `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	// The ID shared by every error within the chain, if one
+	// was generated. Causes do not have an ID of their own.
+	Id string `protobuf:"bytes,9,opt,name=id,proto3" json:"id,omitempty"`
+	// The time in which the error was created.
+	Time          *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_ainsleyclark_errors_v1_errors_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_ainsleyclark_errors_v1_errors_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_ainsleyclark_errors_v1_errors_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Error) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *Error) GetOperation() string {
+	if x != nil {
+		return x.Operation
+	}
+	return ""
+}
+
+func (x *Error) GetFileLine() string {
+	if x != nil {
+		return x.FileLine
+	}
+	return ""
+}
+
+func (x *Error) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Error) GetCauses() []*Error {
+	if x != nil {
+		return x.Causes
+	}
+	return nil
+}
+
+func (x *Error) GetFrames() []*Frame {
+	if x != nil {
+		return x.Frames
+	}
+	return nil
+}
+
+func (x *Error) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *Error) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Error) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+// Frame describes a single function call in a stacktrace.
+type Frame struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Function      string                 `protobuf:"bytes,1,opt,name=function,proto3" json:"function,omitempty"`
+	File          string                 `protobuf:"bytes,2,opt,name=file,proto3" json:"file,omitempty"`
+	Line          int64                  `protobuf:"varint,3,opt,name=line,proto3" json:"line,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Frame) Reset() {
+	*x = Frame{}
+	mi := &file_ainsleyclark_errors_v1_errors_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Frame) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Frame) ProtoMessage() {}
+
+func (x *Frame) ProtoReflect() protoreflect.Message {
+	mi := &file_ainsleyclark_errors_v1_errors_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Frame.ProtoReflect.Descriptor instead.
+func (*Frame) Descriptor() ([]byte, []int) {
+	return file_ainsleyclark_errors_v1_errors_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *Frame) GetFunction() string {
+	if x != nil {
+		return x.Function
+	}
+	return ""
+}
+
+func (x *Frame) GetFile() string {
+	if x != nil {
+		return x.File
+	}
+	return ""
+}
+
+func (x *Frame) GetLine() int64 {
+	if x != nil {
+		return x.Line
+	}
+	return 0
+}
+
+var File_ainsleyclark_errors_v1_errors_proto protoreflect.FileDescriptor
+
+const file_ainsleyclark_errors_v1_errors_proto_rawDesc = "" +
+	"\n" +
+	"#ainsleyclark/errors/v1/errors.proto\x12\x16ainsleyclark.errors.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"\xba\x03\n" +
+	"\x05Error\x12\x12\n" +
+	"\x04code\x18\x01 \x01(\tR\x04code\x12\x18\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\x12\x1c\n" +
+	"\toperation\x18\x03 \x01(\tR\toperation\x12\x1b\n" +
+	"\tfile_line\x18\x04 \x01(\tR\bfileLine\x12G\n" +
+	"\bmetadata\x18\x05 \x03(\v2+.ainsleyclark.errors.v1.Error.MetadataEntryR\bmetadata\x125\n" +
+	"\x06causes\x18\x06 \x03(\v2\x1d.ainsleyclark.errors.v1.ErrorR\x06causes\x125\n" +
+	"\x06frames\x18\a \x03(\v2\x1d.ainsleyclark.errors.v1.FrameR\x06frames\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\x12\x0e\n" +
+	"\x02id\x18\t \x01(\tR\x02id\x12.\n" +
+	"\x04time\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"K\n" +
+	"\x05Frame\x12\x1a\n" +
+	"\bfunction\x18\x01 \x01(\tR\bfunction\x12\x12\n" +
+	"\x04file\x18\x02 \x01(\tR\x04file\x12\x12\n" +
+	"\x04line\x18\x03 \x01(\x03R\x04lineB)Z'github.com/ainsleyclark/errors/errorspbb\x06proto3"
+
+var (
+	file_ainsleyclark_errors_v1_errors_proto_rawDescOnce sync.Once
+	file_ainsleyclark_errors_v1_errors_proto_rawDescData []byte
+)
+
+func file_ainsleyclark_errors_v1_errors_proto_rawDescGZIP() []byte {
+	file_ainsleyclark_errors_v1_errors_proto_rawDescOnce.Do(func() {
+		file_ainsleyclark_errors_v1_errors_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_ainsleyclark_errors_v1_errors_proto_rawDesc), len(file_ainsleyclark_errors_v1_errors_proto_rawDesc)))
+	})
+	return file_ainsleyclark_errors_v1_errors_proto_rawDescData
+}
+
+var file_ainsleyclark_errors_v1_errors_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_ainsleyclark_errors_v1_errors_proto_goTypes = []any{
+	(*Error)(nil),                 // 0: ainsleyclark.errors.v1.Error
+	(*Frame)(nil),                 // 1: ainsleyclark.errors.v1.Frame
+	nil,                           // 2: ainsleyclark.errors.v1.Error.MetadataEntry
+	(*timestamppb.Timestamp)(nil), // 3: google.protobuf.Timestamp
+}
+var file_ainsleyclark_errors_v1_errors_proto_depIdxs = []int32{
+	2, // 0: ainsleyclark.errors.v1.Error.metadata:type_name -> ainsleyclark.errors.v1.Error.MetadataEntry
+	0, // 1: ainsleyclark.errors.v1.Error.causes:type_name -> ainsleyclark.errors.v1.Error
+	1, // 2: ainsleyclark.errors.v1.Error.frames:type_name -> ainsleyclark.errors.v1.Frame
+	3, // 3: ainsleyclark.errors.v1.Error.time:type_name -> google.protobuf.Timestamp
+	4, // [4:4] is the sub-list for method output_type
+	4, // [4:4] is the sub-list for method input_type
+	4, // [4:4] is the sub-list for extension type_name
+	4, // [4:4] is the sub-list for extension extendee
+	0, // [0:4] is the sub-list for field type_name
+}
+
+func init() { file_ainsleyclark_errors_v1_errors_proto_init() }
+func file_ainsleyclark_errors_v1_errors_proto_init() {
+	if File_ainsleyclark_errors_v1_errors_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_ainsleyclark_errors_v1_errors_proto_rawDesc), len(file_ainsleyclark_errors_v1_errors_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_ainsleyclark_errors_v1_errors_proto_goTypes,
+		DependencyIndexes: file_ainsleyclark_errors_v1_errors_proto_depIdxs,
+		MessageInfos:      file_ainsleyclark_errors_v1_errors_proto_msgTypes,
+	}.Build()
+	File_ainsleyclark_errors_v1_errors_proto = out.File
+	file_ainsleyclark_errors_v1_errors_proto_goTypes = nil
+	file_ainsleyclark_errors_v1_errors_proto_depIdxs = nil
+}
@@ -0,0 +1,9 @@
+module github.com/ainsleyclark/errors/errorspb
+
+go 1.23
+
+require github.com/ainsleyclark/errors v0.0.0
+
+require google.golang.org/protobuf v1.36.12
+
+replace github.com/ainsleyclark/errors => ../
@@ -0,0 +1,22 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errorstest
+
+import (
+	"testing"
+
+	"github.com/ainsleyclark/errors"
+)
+
+// WithConfig applies cfg with errors.Configure for the
+// duration of the test, restoring the previous config when
+// the test and its subtests have completed. Tests relying on
+// different configs shouldn't be run in parallel.
+func WithConfig(t testing.TB, cfg errors.Config) {
+	t.Helper()
+	orig := errors.CurrentConfig()
+	t.Cleanup(func() { errors.Configure(orig) })
+	errors.Configure(cfg)
+}
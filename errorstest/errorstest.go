@@ -0,0 +1,136 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package errorstest provides assertions for testing
+// application errors. Each assertion reports a failure
+// with t.Errorf and returns whether it passed, so callers
+// can stop early if needed.
+package errorstest
+
+import (
+	"fmt"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ainsleyclark/errors"
+)
+
+// AssertCode asserts that the code of err is equal to want.
+func AssertCode(t testing.TB, err error, want errors.ErrorCode) bool {
+	t.Helper()
+	if got := errors.Code(err); got != want {
+		t.Errorf("expecting code %q, got %q", want, got)
+		return false
+	}
+	return true
+}
+
+// AssertMessage asserts that the human-readable message of
+// err is equal to want.
+func AssertMessage(t testing.TB, err error, want string) bool {
+	t.Helper()
+	if got := errors.Message(err); got != want {
+		t.Errorf("expecting message %q, got %q", want, got)
+		return false
+	}
+	return true
+}
+
+// AssertOp asserts that the outermost operation within the
+// chain of err is equal to want.
+func AssertOp(t testing.TB, err error, want string) bool {
+	t.Helper()
+	if got := errors.Operation(err); got != want {
+		t.Errorf("expecting operation %q, got %q", want, got)
+		return false
+	}
+	return true
+}
+
+// AssertWraps asserts that target is within the chain of
+// err, as reported by errors.Is.
+func AssertWraps(t testing.TB, err, target error) bool {
+	t.Helper()
+	if !errors.Is(err, target) {
+		t.Errorf("expecting %q to wrap %q", err, target)
+		return false
+	}
+	return true
+}
+
+// AssertFileLine asserts that the file line of err matches
+// want. Want may be a full path or a path relative to any
+// parent directory, such as "store/users.go:27", so tests
+// don't depend on the machine they are run on.
+func AssertFileLine(t testing.TB, err error, want string) bool {
+	t.Helper()
+	if err == nil {
+		t.Errorf("expecting file line %q, got nil error", want)
+		return false
+	}
+	e, ok := errors.AsType[*errors.Error](err)
+	if !ok {
+		t.Errorf("expecting file line %q, got no *Error in chain: %s", want, err)
+		return false
+	}
+	got := filepath.ToSlash(e.FileLine())
+	want = filepath.ToSlash(want)
+	if got != want && !strings.HasSuffix(got, "/"+want) {
+		t.Errorf("expecting file line %q, got %q", want, got)
+		return false
+	}
+	return true
+}
+
+// Equal asserts that the errors are equal, ignoring the
+// program counters. The file line is only compared if want
+// has one. Every differing field is printed on failure.
+func Equal(t testing.TB, want, got *errors.Error) bool {
+	t.Helper()
+	if want == nil || got == nil {
+		if want != got {
+			t.Errorf("expecting %v, got %v", want, got)
+			return false
+		}
+		return true
+	}
+	if diff := Diff(want, got); diff != "" {
+		t.Errorf("errors not equal (-want +got):\n%s", diff)
+		return false
+	}
+	return true
+}
+
+// Diff returns a human-readable description of the fields
+// that differ between want and got, or an empty string if
+// they are equal. See Equal for the fields compared.
+func Diff(want, got *errors.Error) string {
+	var sb strings.Builder
+	field := func(name string, w, g any) {
+		if !reflect.DeepEqual(w, g) {
+			fmt.Fprintf(&sb, "%s:\n\t- %v\n\t+ %v\n", name, w, g)
+		}
+	}
+	field("Code", want.Code, got.Code)
+	field("Message", want.Message, got.Message)
+	field("Operation", want.Operation, got.Operation)
+	field("Err", errString(want.Err), errString(got.Err))
+	if len(want.Metadata) > 0 || len(got.Metadata) > 0 {
+		field("Metadata", want.Metadata, got.Metadata)
+	}
+	if want.FileLine() != "" {
+		field("FileLine", want.FileLine(), got.FileLine())
+	}
+	return sb.String()
+}
+
+// errString returns the string of the error, or "<nil>".
+func errString(err error) string {
+	if err == nil {
+		return "<nil>"
+	}
+	return err.Error()
+}
@@ -0,0 +1,197 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errorstest
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/ainsleyclark/errors"
+)
+
+// recorder is a testing.TB that records failures instead
+// of failing the test.
+type recorder struct {
+	testing.TB
+	failures []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.failures = append(r.failures, fmt.Sprintf(format, args...))
+}
+
+func (r *recorder) Fatalf(format string, args ...any) {
+	r.Errorf(format, args...)
+}
+
+func TestAssertions(t *testing.T) {
+	root := fmt.Errorf("sql: no rows")
+	err := fmt.Errorf("wrap: %w", errors.NewNotFound(root, "User not found", "UserStore.Find"))
+
+	tt := map[string]struct {
+		assert func(t testing.TB) bool
+		want   string
+	}{
+		"Code": {
+			func(t testing.TB) bool { return AssertCode(t, errors.NewNotFound(root, "", ""), errors.NOTFOUND) },
+			"",
+		},
+		"Code Failure": {
+			func(t testing.TB) bool { return AssertCode(t, errors.NewInternal(nil, "", ""), errors.NOTFOUND) },
+			`expecting code "not_found", got "internal"`,
+		},
+		"Message": {
+			func(t testing.TB) bool {
+				return AssertMessage(t, errors.NewNotFound(nil, "User not found", ""), "User not found")
+			},
+			"",
+		},
+		"Message Failure": {
+			func(t testing.TB) bool { return AssertMessage(t, errors.NewNotFound(nil, "message", ""), "other") },
+			`expecting message "other", got "message"`,
+		},
+		"Op": {
+			func(t testing.TB) bool { return AssertOp(t, err, "UserStore.Find") },
+			"",
+		},
+		"Op Failure": {
+			func(t testing.TB) bool { return AssertOp(t, root, "UserStore.Find") },
+			`expecting operation "UserStore.Find", got ""`,
+		},
+		"Wraps": {
+			func(t testing.TB) bool { return AssertWraps(t, err, root) },
+			"",
+		},
+		"Wraps Failure": {
+			func(t testing.TB) bool { return AssertWraps(t, err, fmt.Errorf("other")) },
+			`to wrap "other"`,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			got := test.assert(r)
+			if test.want == "" {
+				if !got || len(r.failures) != 0 {
+					t.Fatalf("expecting pass, got %v", r.failures)
+				}
+				return
+			}
+			if got || len(r.failures) != 1 || !strings.Contains(r.failures[0], test.want) {
+				t.Fatalf("expecting failure containing %s, got %v", test.want, r.failures)
+			}
+		})
+	}
+}
+
+func TestAssertFileLine(t *testing.T) {
+	err := errors.NewInternal(nil, "message", "op")
+
+	tt := map[string]struct {
+		input string
+		want  bool
+	}{
+		"Base Name":    {"errorstest_test.go:94", true},
+		"Relative":     {"errorstest/errorstest_test.go:94", true},
+		"Partial Name": {"test.go:94", false},
+		"Wrong Line":   {"errorstest_test.go:1", false},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			r := &recorder{TB: t}
+			got := AssertFileLine(r, err, test.input)
+			if got != test.want {
+				t.Fatalf("expecting %t, got %t: %v", test.want, got, r.failures)
+			}
+		})
+	}
+
+	t.Run("Nil", func(t *testing.T) {
+		r := &recorder{TB: t}
+		if AssertFileLine(r, nil, "file.go:1") {
+			t.Fatalf("expecting failure for nil error")
+		}
+	})
+
+	t.Run("No Error", func(t *testing.T) {
+		r := &recorder{TB: t}
+		if AssertFileLine(r, fmt.Errorf("error"), "errorstest.go:70") {
+			t.Fatalf("expecting failure for standard error")
+		}
+		if len(r.failures) != 1 || !strings.Contains(r.failures[0], "no *Error in chain") {
+			t.Fatalf("expecting no *Error failure, got %v", r.failures)
+		}
+	})
+}
+
+func TestEqual(t *testing.T) {
+	got := errors.NewNotFound(fmt.Errorf("error"), "message", "op").WithMeta("key", "value")
+
+	t.Run("Equal", func(t *testing.T) {
+		r := &recorder{TB: t}
+		want := &errors.Error{
+			Code:      errors.NOTFOUND,
+			Message:   "message",
+			Operation: "op",
+			Err:       fmt.Errorf("error"),
+			Metadata:  map[string]string{"key": "value"},
+		}
+		if !Equal(r, want, got) {
+			t.Fatalf("expecting equal, got %v", r.failures)
+		}
+	})
+
+	t.Run("Diff", func(t *testing.T) {
+		r := &recorder{TB: t}
+		want := &errors.Error{Code: errors.INTERNAL, Message: "message", Operation: "other"}
+		if Equal(r, want, got) {
+			t.Fatalf("expecting failure")
+		}
+		for _, field := range []string{"Code:", "Operation:", "Err:", "Metadata:"} {
+			if !strings.Contains(r.failures[0], field) {
+				t.Fatalf("expecting diff to contain %s, got %s", field, r.failures[0])
+			}
+		}
+		if strings.Contains(r.failures[0], "Message:") {
+			t.Fatalf("expecting diff to exclude Message, got %s", r.failures[0])
+		}
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		r := &recorder{TB: t}
+		if !Equal(r, nil, nil) || Equal(r, nil, got) {
+			t.Fatalf("expecting nil errors to be compared by identity")
+		}
+	})
+}
+
+func TestAssertGoldenJSON(t *testing.T) {
+	err := errors.NewNotFound(fmt.Errorf("sql: no rows"), "User not found", "UserStore.Find").WithMeta("id", "1")
+	AssertGoldenJSON(t, "not_found", err)
+	if *update {
+		return
+	}
+
+	r := &recorder{TB: t}
+	if AssertGoldenJSON(r, "not_found", errors.NewInternal(nil, "message", "op")) {
+		t.Fatalf("expecting mismatch")
+	}
+}
+
+func TestWithConfig(t *testing.T) {
+	t.Run("Applied", func(t *testing.T) {
+		WithConfig(t, errors.Config{DefaultCode: errors.INVALID})
+		AssertCode(t, errors.NewE(nil, "message", "op"), errors.INVALID)
+	})
+
+	t.Run("Restored", func(t *testing.T) {
+		AssertCode(t, errors.NewE(nil, "message", "op"), errors.INTERNAL)
+	})
+}
@@ -0,0 +1,83 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errorstest
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"os"
+	"path"
+	"path/filepath"
+	"testing"
+)
+
+// update rewrites golden files with the actual output when
+// tests are run with -errorstest.update.
+var update = flag.Bool("errorstest.update", false, "update errorstest golden files")
+
+// GoldenDir is the directory golden files are read from and
+// written to, relative to the package under test.
+var GoldenDir = "testdata"
+
+// AssertGolden asserts that got is equal to the contents of
+// the golden file testdata/<name>.golden. When tests are
+// run with -errorstest.update, the file is written instead.
+func AssertGolden(t testing.TB, name string, got []byte) bool {
+	t.Helper()
+
+	file := filepath.Join(GoldenDir, name+".golden")
+	if *update {
+		if err := os.MkdirAll(GoldenDir, os.ModePerm); err != nil {
+			t.Fatalf("creating golden directory: %s", err)
+		}
+		if err := os.WriteFile(file, got, 0o644); err != nil {
+			t.Fatalf("writing golden file: %s", err)
+		}
+		return true
+	}
+
+	want, err := os.ReadFile(file)
+	if err != nil {
+		t.Fatalf("reading golden file, run with -errorstest.update to create it: %s", err)
+	}
+	if !bytes.Equal(want, got) {
+		t.Errorf("output does not match %s:\nwant:\n%s\ngot:\n%s", file, want, got)
+		return false
+	}
+	return true
+}
+
+// AssertGoldenJSON marshals err to indented JSON and asserts
+// that it is equal to the golden file testdata/<name>.golden.
+// The file_line field is reduced to its base name so golden
+// files are independent of the machine they were created on,
+// and the id and time fields are removed as they differ
+// between runs.
+func AssertGoldenJSON(t testing.TB, name string, err error) bool {
+	t.Helper()
+
+	buf, mErr := json.Marshal(err)
+	if mErr != nil {
+		t.Fatalf("marshalling error: %s", mErr)
+	}
+
+	var m map[string]any
+	if mErr = json.Unmarshal(buf, &m); mErr != nil {
+		t.Fatalf("unmarshalling error: %s", mErr)
+	}
+	if fl, ok := m["file_line"].(string); ok && fl != "" {
+		m["file_line"] = path.Base(filepath.ToSlash(fl))
+	}
+	delete(m, "id")
+	delete(m, "time")
+
+	got, mErr := json.MarshalIndent(m, "", "\t")
+	if mErr != nil {
+		t.Fatalf("marshalling error: %s", mErr)
+	}
+
+	return AssertGolden(t, name, append(got, '\n'))
+}
@@ -0,0 +1,138 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"sync"
+)
+
+// Exit statuses for command-line tools, following the
+// conventions of BSD's sysexits.h.
+const (
+	// ExitOK - Successful termination.
+	ExitOK = 0
+	// ExitFailure - General failure.
+	ExitFailure = 1
+	// ExitUsage - The command was used incorrectly.
+	ExitUsage = 64
+	// ExitDataErr - The input data was incorrect.
+	ExitDataErr = 65
+	// ExitNoInput - An input file or entity did not exist.
+	ExitNoInput = 66
+	// ExitUnavailable - A service is unavailable.
+	ExitUnavailable = 69
+	// ExitSoftware - An internal software error.
+	ExitSoftware = 70
+	// ExitCantCreate - An output file cannot be created.
+	ExitCantCreate = 73
+	// ExitIOErr - An error occurred while doing I/O.
+	ExitIOErr = 74
+	// ExitTempFail - A temporary failure, the user is
+	// invited to retry.
+	ExitTempFail = 75
+	// ExitNoPerm - Insufficient permission.
+	ExitNoPerm = 77
+	// ExitConfig - Something was found in an unconfigured
+	// or misconfigured state.
+	ExitConfig = 78
+	// ExitInterrupted is the conventional status of a
+	// process terminated by SIGINT.
+	ExitInterrupted = 130
+)
+
+// VerboseEnv is the environment variable that, when set to
+// a true value such as "1", makes Main print the full
+// error detail and stacktrace instead of the message.
+const VerboseEnv = "ERRORS_VERBOSE"
+
+var (
+	exitCodesMtx sync.RWMutex
+	// exitCodes maps the error codes to their exit status.
+	exitCodes = map[ErrorCode]int{
+		CONFLICT:            ExitCantCreate,
+		INTERNAL:            ExitSoftware,
+		INVALID:             ExitDataErr,
+		NOTFOUND:            ExitNoInput,
+		UNKNOWN:             ExitSoftware,
+		MAXIMUMATTEMPTS:     ExitTempFail,
+		EXPIRED:             ExitNoPerm,
+		CANCELLED:           ExitInterrupted,
+		DEADLINE_EXCEEDED:   ExitTempFail,
+		UNAUTHENTICATED:     ExitNoPerm,
+		PERMISSION_DENIED:   ExitNoPerm,
+		UNAVAILABLE:         ExitUnavailable,
+		UNIMPLEMENTED:       ExitSoftware,
+		FAILED_PRECONDITION: ExitConfig,
+		ALREADY_EXISTS:      ExitCantCreate,
+		ABORTED:             ExitTempFail,
+		OUT_OF_RANGE:        ExitDataErr,
+		DATA_LOSS:           ExitIOErr,
+		RESOURCE_EXHAUSTED:  ExitTempFail,
+	}
+)
+
+// SetExitCode configures the exit status returned by
+// ExitCode for the given error code, overriding the
+// default.
+func SetExitCode(code ErrorCode, status int) {
+	exitCodesMtx.Lock()
+	defer exitCodesMtx.Unlock()
+	exitCodes[code] = status
+}
+
+// ExitCode returns the process exit status for the error,
+// obtained from the status configured for its code or the
+// nearest parent for hierarchical codes. Codes without a
+// status return ExitFailure.
+// If err is nil, ExitCode returns ExitOK.
+func ExitCode(err error) int {
+	if err == nil {
+		return ExitOK
+	}
+	// Resolve the code before locking, as classifiers may
+	// configure exit codes.
+	code := Code(err)
+	exitCodesMtx.RLock()
+	defer exitCodesMtx.RUnlock()
+	for ; code != ""; code = code.Parent() {
+		if status, ok := exitCodes[code]; ok {
+			return status
+		}
+	}
+	return ExitFailure
+}
+
+// Main runs fn as the body of a command-line tool. If fn
+// returns an error, the human-readable message is printed
+// to stderr and the process exits with the status returned
+// by ExitCode. When the VerboseEnv environment variable is
+// set, the full error is printed with %+v instead.
+//
+// Main should be the last call in the main function as
+// deferred functions are not run on exit.
+func Main(fn func() error) {
+	if status := run(fn, os.Stderr, os.Getenv); status != ExitOK {
+		os.Exit(status)
+	}
+}
+
+// run calls fn and writes any error to w, returning the
+// exit status.
+func run(fn func() error, w io.Writer, getenv func(string) string) int {
+	err := fn()
+	if err == nil {
+		return ExitOK
+	}
+	if verbose, _ := strconv.ParseBool(getenv(VerboseEnv)); verbose {
+		fmt.Fprintf(w, "%+v\n", err)
+	} else {
+		fmt.Fprintln(w, Message(err))
+	}
+	return ExitCode(err)
+}
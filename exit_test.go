@@ -0,0 +1,156 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestExitCode(t *testing.T) {
+	tt := map[string]struct {
+		input error
+		want  int
+	}{
+		"Nil": {
+			nil,
+			ExitOK,
+		},
+		"Invalid": {
+			NewInvalid(nil, "message", "op"),
+			ExitDataErr,
+		},
+		"Internal": {
+			NewInternal(nil, "message", "op"),
+			ExitSoftware,
+		},
+		"Sub Code": {
+			&Error{Code: "not_found.user"},
+			ExitNoInput,
+		},
+		"Wrapped": {
+			fmt.Errorf("wrap: %w", NewDeadlineExceeded(nil, "message", "op")),
+			ExitTempFail,
+		},
+		"Unregistered Code": {
+			&Error{Code: "unregistered"},
+			ExitFailure,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := ExitCode(test.input)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %d, got %d", test.want, got)
+			}
+		})
+	}
+}
+
+func TestSetExitCode(t *testing.T) {
+	const code = "test_exit"
+	t.Cleanup(func() {
+		exitCodesMtx.Lock()
+		delete(exitCodes, code)
+		exitCodesMtx.Unlock()
+	})
+
+	err := &Error{Code: code}
+	if got := ExitCode(err); got != ExitFailure {
+		t.Fatalf("expecting %d, got %d", ExitFailure, got)
+	}
+	SetExitCode(code, ExitConfig)
+	if got := ExitCode(err); got != ExitConfig {
+		t.Fatalf("expecting %d, got %d", ExitConfig, got)
+	}
+}
+
+func TestMain_Run(t *testing.T) {
+	tt := map[string]struct {
+		input   error
+		verbose string
+		status  int
+		want    string
+	}{
+		"Success": {
+			nil,
+			"",
+			ExitOK,
+			"",
+		},
+		"Message": {
+			NewInvalid(nil, "Invalid config file", "op"),
+			"",
+			ExitDataErr,
+			"Invalid config file\n",
+		},
+		"Standard Error": {
+			fmt.Errorf("error"),
+			"",
+			ExitSoftware,
+			GlobalError + "\n",
+		},
+		"Verbose": {
+			NewInvalid(nil, "Invalid config file", "op"),
+			"1",
+			ExitDataErr,
+			"github.com/ainsleyclark/errors.TestMain_Run",
+		},
+		"Verbose False": {
+			NewInvalid(nil, "Invalid config file", "op"),
+			"false",
+			ExitDataErr,
+			"Invalid config file\n",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			getenv := func(key string) string {
+				if key == VerboseEnv {
+					return test.verbose
+				}
+				return ""
+			}
+			status := run(func() error { return test.input }, buf, getenv)
+			if status != test.status {
+				t.Fatalf("expecting %d, got %d", test.status, status)
+			}
+			if !strings.Contains(buf.String(), test.want) {
+				t.Fatalf("expecting %s to contain, got %s", test.want, buf.String())
+			}
+		})
+	}
+}
+
+func TestExitCode_Classifier(t *testing.T) {
+	const code = "test_classified_exit"
+	t.Cleanup(func() {
+		classifiersMtx.Lock()
+		classifiers = nil
+		classifiersMtx.Unlock()
+		exitCodesMtx.Lock()
+		delete(exitCodes, code)
+		exitCodesMtx.Unlock()
+	})
+
+	custom := fmt.Errorf("custom")
+	RegisterClassifier(func(err error) ErrorCode {
+		if Is(err, custom) {
+			SetExitCode(code, ExitConfig)
+			return code
+		}
+		return ""
+	})
+
+	if got := ExitCode(custom); got != ExitConfig {
+		t.Fatalf("expecting %d, got %d", ExitConfig, got)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "strings"
+
+// FormatOptions defines the layout of the string returned
+// by Error. The zero value produces the default layout:
+//
+//	<code> file:line - op: err, message
+type FormatOptions struct {
+	// Omits the error code.
+	OmitCode bool
+	// Omits the file and line in which the error occurred.
+	OmitFileLine bool
+	// Omits the operation.
+	OmitOperation bool
+	// Omits the wrapped error.
+	OmitErr bool
+	// Omits the human-readable message.
+	OmitMessage bool
+	// Prints the message before the wrapped error.
+	MessageFirst bool
+	// The separator between the wrapped error and message,
+	// defaults to ", ".
+	Separator string
+}
+
+// FormatWith returns the string representation of err using
+// the given options, which are applied to every Error in
+// the chain. Errors that aren't of type Error return the
+// result of their Error method.
+// If err is nil, FormatWith returns an empty string.
+func FormatWith(err error, opts FormatOptions) string {
+	if err == nil {
+		return ""
+	}
+	if e, ok := err.(*Error); ok {
+		return opts.Format(e)
+	}
+	return err.Error()
+}
+
+// Format returns the string representation of the error
+// using the options, which are applied to every Error in
+// the chain.
+func (o FormatOptions) Format(e *Error) string {
+	return o.format(e, true)
+}
+
+// format returns the string representation of the error.
+// If chain is false, wrapped errors are printed with their
+// own Error method.
+func (o FormatOptions) format(e *Error, chain bool) string {
+	sep := o.Separator
+	if sep == "" {
+		sep = ", "
+	}
+
+	var code, fileLine, op, err, msg string
+	if !o.OmitCode && e.Code != "" {
+		code = string(e.Code)
+	}
+	if !o.OmitFileLine {
+		fileLine = e.FileLine()
+	}
+	if !o.OmitOperation {
+		op = e.Operation
+	}
+	if !o.OmitErr && e.Err != nil {
+		if inner, ok := e.Err.(*Error); ok && chain {
+			err = o.format(inner, true)
+		} else {
+			err = e.Err.Error()
+		}
+	}
+	if !o.OmitMessage {
+		msg = e.Message
+	}
+
+	first, second := err, msg
+	if o.MessageFirst {
+		first, second = msg, err
+	}
+
+	// Size the buffer up front so the string is built with
+	// a single allocation.
+	var sb strings.Builder
+	sb.Grow(len(code) + len(fileLine) + len(op) + len(first) + len(second) + len(sep) + 8)
+
+	if code != "" {
+		sb.WriteString("<")
+		sb.WriteString(code)
+		sb.WriteString("> ")
+	}
+	if fileLine != "" {
+		sb.WriteString(fileLine)
+		sb.WriteString(" - ")
+	}
+	if op != "" {
+		sb.WriteString(op)
+		sb.WriteString(": ")
+	}
+	sb.WriteString(first)
+	if first != "" && second != "" {
+		sb.WriteString(sep)
+	}
+	sb.WriteString(second)
+
+	return strings.TrimSuffix(strings.TrimSpace(sb.String()), ",")
+}
@@ -0,0 +1,147 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestFormatWith(t *testing.T) {
+	e := &Error{
+		Code:      INTERNAL,
+		Message:   "message",
+		Operation: "op",
+		Err:       &Error{Code: NOTFOUND, Operation: "inner", Err: fmt.Errorf("err")},
+		fileLine:  "file.go:10",
+	}
+
+	tt := map[string]struct {
+		input error
+		opts  FormatOptions
+		want  string
+	}{
+		"Nil": {
+			nil,
+			FormatOptions{},
+			"",
+		},
+		"Standard Error": {
+			fmt.Errorf("err"),
+			FormatOptions{OmitCode: true},
+			"err",
+		},
+		"Default": {
+			e,
+			FormatOptions{},
+			"<internal> file.go:10 - op: <not_found> inner: err, message",
+		},
+		"Omit Code": {
+			e,
+			FormatOptions{OmitCode: true},
+			"file.go:10 - op: inner: err, message",
+		},
+		"Omit File Line": {
+			e,
+			FormatOptions{OmitFileLine: true},
+			"<internal> op: <not_found> inner: err, message",
+		},
+		"Omit Operation": {
+			e,
+			FormatOptions{OmitOperation: true, OmitFileLine: true},
+			"<internal> <not_found> err, message",
+		},
+		"Omit Err": {
+			e,
+			FormatOptions{OmitErr: true},
+			"<internal> file.go:10 - op: message",
+		},
+		"Omit Message": {
+			e,
+			FormatOptions{OmitMessage: true, OmitCode: true},
+			"file.go:10 - op: inner: err",
+		},
+		"Message First": {
+			e,
+			FormatOptions{MessageFirst: true, Separator: ": ", OmitCode: true, OmitFileLine: true, OmitOperation: true},
+			"message: err",
+		},
+		"Separator": {
+			e,
+			FormatOptions{Separator: " | ", OmitCode: true, OmitFileLine: true, OmitOperation: true},
+			"err | message",
+		},
+		"Message Only": {
+			e,
+			FormatOptions{OmitCode: true, OmitFileLine: true, OmitOperation: true, OmitErr: true},
+			"message",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := FormatWith(test.input, test.opts)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestError_ErrorFormat(t *testing.T) {
+	e := &Error{Code: INTERNAL, Message: "message", Operation: "op", Err: fmt.Errorf("err")}
+
+	t.Run("Default Format", func(t *testing.T) {
+		withConfig(t, func(cfg *Config) { cfg.Format = FormatOptions{OmitCode: true, MessageFirst: true} })
+		want := "op: message, err"
+		if got := e.Error(); !reflect.DeepEqual(want, got) {
+			t.Fatalf("expecting %s, got %s", want, got)
+		}
+	})
+
+	t.Run("Formatter", func(t *testing.T) {
+		withConfig(t, func(cfg *Config) {
+			cfg.Formatter = func(e *Error) string {
+				return strings.ToUpper(e.Message)
+			}
+		})
+		want := "MESSAGE"
+		if got := e.Error(); !reflect.DeepEqual(want, got) {
+			t.Fatalf("expecting %s, got %s", want, got)
+		}
+	})
+
+	t.Run("Allocations", func(t *testing.T) {
+		e := &Error{Code: INTERNAL, Message: "message", Operation: "op", Err: errPlain("err"), fileLine: "file.go:10"}
+		got := testing.AllocsPerRun(100, func() { _ = e.Error() })
+		if got > 1 {
+			t.Fatalf("expecting at most 1 allocation, got %v", got)
+		}
+	})
+}
+
+type errPlain string
+
+func (e errPlain) Error() string { return string(e) }
+
+func TestFormatWith_GlobalConfig(t *testing.T) {
+	withConfig(t, func(cfg *Config) { cfg.Format = FormatOptions{OmitFileLine: true} })
+	e := &Error{
+		Code:     INTERNAL,
+		Message:  "message",
+		Err:      &Error{Code: NOTFOUND, Err: fmt.Errorf("err"), fileLine: "inner.go:20"},
+		fileLine: "outer.go:10",
+	}
+	want := "<internal> outer.go:10 - <not_found> inner.go:20 - err, message"
+	if got := FormatWith(e, FormatOptions{}); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+	want = "<internal> <not_found> err, message"
+	if got := e.Error(); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
@@ -0,0 +1,163 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// LabelKey is the metadata key used for the label of the
+// goroutine that returned an error within a Group.
+const LabelKey = "label"
+
+// GroupOptions defines the options for a Group.
+type GroupOptions struct {
+	// The maximum amount of goroutines running at once,
+	// zero means no limit.
+	Limit int
+	// When true, every goroutine is run to completion and
+	// Wait returns all errors joined together. Otherwise,
+	// the context is cancelled on the first error, which
+	// is returned by Wait.
+	Collect bool
+}
+
+// Group runs goroutines with a shared context and
+// aggregates the errors they return, in a similar manner
+// to golang.org/x/sync/errgroup. Panics are recovered and
+// returned as INTERNAL errors.
+//
+// A zero Group is valid, has no limit and does not cancel
+// on error.
+type Group struct {
+	opts   GroupOptions
+	cancel context.CancelCauseFunc
+	wg     sync.WaitGroup
+	sem    chan struct{}
+	mtx    sync.Mutex
+	errs   []error
+}
+
+// NewGroup returns a Group and a context derived from ctx,
+// which is cancelled when a goroutine fails (unless the
+// group collects errors) or when Wait returns.
+func NewGroup(ctx context.Context, opts GroupOptions) (*Group, context.Context) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	g := &Group{
+		opts:   opts,
+		cancel: cancel,
+	}
+	if opts.Limit > 0 {
+		g.sem = make(chan struct{}, opts.Limit)
+	}
+	return g, ctx
+}
+
+// Go calls fn in a new goroutine, blocking until a slot is
+// available if the group has a limit. Any error returned,
+// or panic raised, is tagged with the label under LabelKey
+// in the metadata.
+func (g *Group) Go(label string, fn func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+	g.wg.Add(1)
+	go func() {
+		defer g.done()
+		if err := g.call(label, fn); err != nil {
+			g.fail(err)
+		}
+	}()
+}
+
+// Wait blocks until all goroutines have returned, then
+// returns the first error, or all errors joined together
+// if the group collects errors.
+func (g *Group) Wait() error {
+	g.wg.Wait()
+	if g.cancel != nil {
+		g.cancel(nil)
+	}
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	if len(g.errs) == 0 {
+		return nil
+	}
+	if !g.opts.Collect {
+		return g.errs[0]
+	}
+	return errors.Join(g.errs...)
+}
+
+// call runs fn, recovering any panic into an INTERNAL
+// error, and labels the returned error.
+func (g *Group) call(label string, fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			perr, ok := r.(error)
+			if !ok {
+				perr = fmt.Errorf("%v", r)
+			}
+			err = newPanicError(fmt.Errorf("panic: %w", perr))
+		}
+		if err != nil && label != "" {
+			err = &Error{Err: err, Metadata: map[string]string{LabelKey: label}}
+		}
+	}()
+	return fn()
+}
+
+// newPanicError returns an INTERNAL error for a recovered
+// panic, with the file line and stack starting at the frame
+// that panicked rather than within the runtime. It must be
+// called directly from the deferred function that recovered.
+func newPanicError(err error) *Error {
+	e := newErrorSkip(0, err, "", INTERNAL, "")
+	inRuntime := false
+	for i, pc := range e.pcs {
+		if pc == 0 {
+			break
+		}
+		frame, _ := runtime.CallersFrames(e.pcs[i : i+1]).Next()
+		if strings.HasPrefix(frame.Function, "runtime.") {
+			inRuntime = true
+			continue
+		}
+		if inRuntime {
+			e.pcs = e.pcs[i:]
+			e.fileLine = frame.File + ":" + strconv.Itoa(frame.Line)
+			break
+		}
+	}
+	return e
+}
+
+// fail records the error and cancels the context if the
+// group does not collect errors.
+func (g *Group) fail(err error) {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	if len(g.errs) > 0 && !g.opts.Collect {
+		return
+	}
+	g.errs = append(g.errs, err)
+	if g.cancel != nil && !g.opts.Collect {
+		g.cancel(err)
+	}
+}
+
+// done releases the goroutine's slot.
+func (g *Group) done() {
+	if g.sem != nil {
+		<-g.sem
+	}
+	g.wg.Done()
+}
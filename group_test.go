@@ -0,0 +1,168 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestGroup_Wait(t *testing.T) {
+	tt := map[string]struct {
+		collect bool
+		fns     map[string]func() error
+		want    []string
+	}{
+		"Success": {
+			false,
+			map[string]func() error{
+				"a": func() error { return nil },
+				"b": func() error { return nil },
+			},
+			nil,
+		},
+		"First Error": {
+			false,
+			map[string]func() error{
+				"a": func() error { return NewNotFound(nil, "message", "op") },
+			},
+			[]string{"message"},
+		},
+		"Collect": {
+			true,
+			map[string]func() error{
+				"a": func() error { return NewNotFound(nil, "a", "op") },
+				"b": func() error { return NewInvalid(nil, "b", "op") },
+				"c": func() error { return nil },
+			},
+			[]string{"a", "b"},
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			g, _ := NewGroup(context.Background(), GroupOptions{Collect: test.collect})
+			for label, fn := range test.fns {
+				g.Go(label, fn)
+			}
+			err := g.Wait()
+			if test.want == nil {
+				if err != nil {
+					t.Fatalf("expecting nil, got %s", err)
+				}
+				return
+			}
+			if err == nil {
+				t.Fatalf("expecting %s, got nil", test.want)
+			}
+			for _, want := range test.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Fatalf("expecting %s to contain %s", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestGroup_Label(t *testing.T) {
+	sentinel := NewNotFound(nil, "message", "op")
+	g, _ := NewGroup(context.Background(), GroupOptions{})
+	g.Go("fetch-users", func() error { return sentinel })
+
+	err := g.Wait()
+	want := map[string]string{LabelKey: "fetch-users"}
+	if got := Metadata(err); !reflect.DeepEqual(want, got) {
+		t.Fatalf("expecting %v, got %v", want, got)
+	}
+	if !errors.Is(err, sentinel) || Code(err) != NOTFOUND {
+		t.Fatalf("expecting labelled error to wrap the original, got %s", err)
+	}
+	if len(sentinel.Metadata) != 0 {
+		t.Fatalf("expecting original error to be unchanged, got %v", sentinel.Metadata)
+	}
+}
+
+func TestGroup_Panic(t *testing.T) {
+	g, _ := NewGroup(context.Background(), GroupOptions{})
+	g.Go("worker", func() error { panic("boom") })
+
+	err := g.Wait()
+	if Code(err) != INTERNAL {
+		t.Fatalf("expecting %s, got %s", INTERNAL, Code(err))
+	}
+	if !strings.Contains(err.Error(), "panic: boom") {
+		t.Fatalf("expecting panic message, got %s", err)
+	}
+	if Metadata(err)[LabelKey] != "worker" {
+		t.Fatalf("expecting label, got %v", Metadata(err))
+	}
+	if op := Operation(err); op != "" {
+		t.Fatalf("expecting no operation, got %s", op)
+	}
+	e := Find(err, func(e *Error) bool { return e.Code == INTERNAL })
+	if e == nil || !strings.HasSuffix(e.FileLine(), "/group_test.go:94") {
+		t.Fatalf("expecting panic file line, got %v", e)
+	}
+}
+
+func TestGroup_Cancel(t *testing.T) {
+	g, ctx := NewGroup(context.Background(), GroupOptions{})
+	failure := NewInternal(nil, "message", "op")
+	g.Go("fail", func() error { return failure })
+	g.Go("wait", func() error {
+		<-ctx.Done()
+		return ctx.Err()
+	})
+
+	if err := g.Wait(); !errors.Is(err, failure) {
+		t.Fatalf("expecting %s, got %s", failure, err)
+	}
+	if cause := context.Cause(ctx); !errors.Is(cause, failure) {
+		t.Fatalf("expecting cause %s, got %s", failure, cause)
+	}
+}
+
+func TestGroup_Limit(t *testing.T) {
+	const limit = 2
+	var (
+		running atomic.Int32
+		maximum atomic.Int32
+	)
+	g, _ := NewGroup(context.Background(), GroupOptions{Limit: limit})
+	for i := 0; i < 10; i++ {
+		g.Go("", func() error {
+			n := running.Add(1)
+			for {
+				m := maximum.Load()
+				if n <= m || maximum.CompareAndSwap(m, n) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			running.Add(-1)
+			return nil
+		})
+	}
+
+	if err := g.Wait(); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	if got := maximum.Load(); got > limit {
+		t.Fatalf("expecting at most %d goroutines, got %d", limit, got)
+	}
+}
+
+func TestGroup_Zero(t *testing.T) {
+	var g Group
+	g.Go("", func() error { return nil })
+	if err := g.Wait(); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+}
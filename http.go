@@ -0,0 +1,24 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import "net/http"
+
+// WriteHTTP writes the human-readable message of the error
+// to the response, with the status obtained from its code.
+// If the error has an ID, it's set in the IDHeader and
+// appended to the message, so users can quote it to
+// support. If err is nil, WriteHTTP is a no-op.
+func WriteHTTP(w http.ResponseWriter, err error) {
+	if err == nil {
+		return
+	}
+	msg := Message(err)
+	if id := ID(err); id != "" {
+		w.Header().Set(IDHeader, id)
+		msg += " (ID: " + id + ")"
+	}
+	http.Error(w, msg, Code(err).HTTPStatus())
+}
@@ -0,0 +1,59 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+)
+
+func TestWriteHTTP(t *testing.T) {
+	withID := NewNotFound(nil, "User not found", "op")
+	withID.id = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+
+	tt := map[string]struct {
+		input  error
+		status int
+		body   string
+		header string
+	}{
+		"Nil": {
+			nil,
+			http.StatusOK,
+			"",
+			"",
+		},
+		"Message": {
+			NewInvalid(nil, "Invalid email", "op"),
+			http.StatusBadRequest,
+			"Invalid email\n",
+			"",
+		},
+		"ID": {
+			withID,
+			http.StatusNotFound,
+			"User not found (ID: 01ARZ3NDEKTSV4RRFFQ69G5FAV)\n",
+			"01ARZ3NDEKTSV4RRFFQ69G5FAV",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			WriteHTTP(rr, test.input)
+			if rr.Code != test.status {
+				t.Fatalf("expecting %d, got %d", test.status, rr.Code)
+			}
+			if !reflect.DeepEqual(test.body, rr.Body.String()) {
+				t.Fatalf("expecting %s, got %s", test.body, rr.Body.String())
+			}
+			if got := rr.Header().Get(IDHeader); got != test.header {
+				t.Fatalf("expecting %s, got %s", test.header, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,96 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"crypto/rand"
+	"sync"
+	"time"
+)
+
+// IDHeader is the HTTP header used for the ID of an error
+// written by WriteHTTP.
+const IDHeader = "X-Error-ID"
+
+// crockford is the base32 alphabet used for IDs, which
+// excludes I, L, O and U to avoid confusion when read
+// aloud or copied by hand.
+const crockford = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+
+var (
+	idMtx sync.Mutex
+	// lastTime and lastEntropy are used to keep IDs
+	// generated within the same millisecond sortable.
+	lastTime    int64
+	lastEntropy [10]byte
+)
+
+// ID returns the ID of the outermost Error within the chain
+// that has one. IDs are assigned when Config.GenerateIDs is
+// enabled and are propagated outward when wrapped, so the
+// ID of a chain is shared by every error within it.
+// If no ID is present, ID returns an empty string.
+func ID(err error) string {
+	for ; err != nil; err = Unwrap(err) {
+		if e, ok := err.(*Error); ok && e.id != "" {
+			return e.id
+		}
+	}
+	return ""
+}
+
+// WithID sets the ID of the error and returns the error to
+// allow for chaining. It's intended for restoring errors
+// decoded from other formats.
+func (e *Error) WithID(id string) *Error {
+	e.id = id
+	return e
+}
+
+// newID returns a 26 character, lexicographically sortable
+// identifier in the same format as a ULID, consisting of a
+// millisecond timestamp followed by 80 bits of randomness.
+// IDs generated within the same millisecond increment the
+// randomness of the previous ID, so they remain ordered.
+func newID(now time.Time) string {
+	ms := now.UnixMilli()
+
+	idMtx.Lock()
+	if ms <= lastTime {
+		ms = lastTime
+		for i := len(lastEntropy) - 1; i >= 0; i-- {
+			lastEntropy[i]++
+			if lastEntropy[i] != 0 {
+				break
+			}
+		}
+	} else {
+		lastTime = ms
+		_, _ = rand.Read(lastEntropy[:])
+	}
+	var b [16]byte
+	b[0], b[1], b[2] = byte(ms>>40), byte(ms>>32), byte(ms>>24)
+	b[3], b[4], b[5] = byte(ms>>16), byte(ms>>8), byte(ms)
+	copy(b[6:], lastEntropy[:])
+	idMtx.Unlock()
+
+	return encodeID(b)
+}
+
+// encodeID encodes the 128 bits as 26 base32 characters,
+// the first of which only holds the top 3 bits.
+func encodeID(b [16]byte) string {
+	var out [26]byte
+	hi := uint64(b[0])<<56 | uint64(b[1])<<48 | uint64(b[2])<<40 | uint64(b[3])<<32 |
+		uint64(b[4])<<24 | uint64(b[5])<<16 | uint64(b[6])<<8 | uint64(b[7])
+	lo := uint64(b[8])<<56 | uint64(b[9])<<48 | uint64(b[10])<<40 | uint64(b[11])<<32 |
+		uint64(b[12])<<24 | uint64(b[13])<<16 | uint64(b[14])<<8 | uint64(b[15])
+	for i := len(out) - 1; i >= 0; i-- {
+		out[i] = crockford[lo&31]
+		lo = lo>>5 | hi<<59
+		hi >>= 5
+	}
+	return string(out[:])
+}
@@ -0,0 +1,151 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestID(t *testing.T) {
+	withConfig(t, func(cfg *Config) { cfg.GenerateIDs = true })
+	inner := NewNotFound(nil, "message", "op")
+
+	tt := map[string]struct {
+		input error
+		want  string
+	}{
+		"Nil": {
+			nil,
+			"",
+		},
+		"Standard Error": {
+			fmt.Errorf("error"),
+			"",
+		},
+		"Error": {
+			inner,
+			inner.id,
+		},
+		"Propagated": {
+			NewInternal(fmt.Errorf("wrap: %w", inner), "message", "op"),
+			inner.id,
+		},
+		"Wrapped": {
+			fmt.Errorf("wrap: %w", inner),
+			inner.id,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := ID(test.input)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestID_Disabled(t *testing.T) {
+	if got := ID(NewInternal(nil, "message", "op")); got != "" {
+		t.Fatalf("expecting no ID, got %s", got)
+	}
+}
+
+func TestError_WithID(t *testing.T) {
+	const want = "01ARZ3NDEKTSV4RRFFQ69G5FAV"
+	got := ID(NewInternal(nil, "message", "op").WithID(want))
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
+
+func TestNewID(t *testing.T) {
+	now := time.Date(2022, 1, 1, 0, 0, 0, 0, time.UTC)
+	ids := make([]string, 0, 1000)
+	for i := 0; i < 1000; i++ {
+		ids = append(ids, newID(now.Add(time.Duration(i%3)*time.Millisecond)))
+	}
+
+	seen := make(map[string]struct{}, len(ids))
+	for _, id := range ids {
+		if len(id) != 26 {
+			t.Fatalf("expecting 26 characters, got %d", len(id))
+		}
+		if strings.Trim(id, crockford) != "" {
+			t.Fatalf("expecting base32 characters, got %s", id)
+		}
+		if _, ok := seen[id]; ok {
+			t.Fatalf("expecting unique IDs, got duplicate %s", id)
+		}
+		seen[id] = struct{}{}
+	}
+	if !sort.StringsAreSorted(ids) {
+		t.Fatal("expecting IDs to be sortable by creation")
+	}
+}
+
+func TestEncodeID(t *testing.T) {
+	tt := map[string]struct {
+		input [16]byte
+		want  string
+	}{
+		"Zero": {
+			[16]byte{},
+			"00000000000000000000000000",
+		},
+		"Max": {
+			[16]byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			"7ZZZZZZZZZZZZZZZZZZZZZZZZZ",
+		},
+		"Timestamp": {
+			[16]byte{0x01, 0x56, 0x3d, 0xf3, 0x64, 0x81},
+			"01ARYZ6S410000000000000000",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := encodeID(test.input)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestError_JSONID(t *testing.T) {
+	withConfig(t, func(cfg *Config) { cfg.GenerateIDs = true })
+	e := NewNotFound(nil, "message", "op")
+
+	buf, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	if !strings.Contains(string(buf), `"id":"`+e.id+`"`) {
+		t.Fatalf("expecting %s to contain id", buf)
+	}
+	got := &Error{}
+	if err = json.Unmarshal(buf, got); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	if ID(got) != e.id {
+		t.Fatalf("expecting %s, got %s", e.id, ID(got))
+	}
+}
+
+func TestNewEvent_ErrorID(t *testing.T) {
+	withConfig(t, func(cfg *Config) { cfg.GenerateIDs = true })
+	e := NewInternal(nil, "message", "op")
+	if got := NewEvent(e).ErrorID; got != e.id {
+		t.Fatalf("expecting %s, got %s", e.id, got)
+	}
+}
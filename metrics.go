@@ -0,0 +1,181 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"bufio"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// OtherOperation is the operation label used once the
+// maximum amount of distinct operations has been reached.
+const OtherOperation = "other"
+
+// MetricsOptions defines the options for Metrics. Zero
+// values are replaced by their defaults.
+type MetricsOptions struct {
+	// The name of the counter, defaults to "errors_total".
+	Name string
+	// The maximum amount of distinct operation labels, any
+	// further operations are counted as OtherOperation.
+	// Defaults to 100.
+	MaxOperations int
+}
+
+// Metrics counts observed errors by code, operation and
+// severity. Counters are updated without locking, so
+// Observe is safe to call on hot paths.
+//
+// Metrics implements http.Handler, serving the counters
+// in the Prometheus text exposition format.
+type Metrics struct {
+	opts       MetricsOptions
+	counters   sync.Map // map[metricKey]*atomic.Uint64
+	operations sync.Map // map[string]struct{}
+	opCount    atomic.Int64
+}
+
+// metricKey defines the labels of a counter.
+type metricKey struct {
+	code      string
+	operation string
+	severity  string
+}
+
+// DefaultMetrics is the registry used by Observe and
+// MetricsHandler.
+var DefaultMetrics = NewMetrics(MetricsOptions{})
+
+// NewMetrics returns an empty Metrics registry.
+func NewMetrics(opts MetricsOptions) *Metrics {
+	if opts.Name == "" {
+		opts.Name = "errors_total"
+	}
+	if opts.MaxOperations <= 0 {
+		opts.MaxOperations = 100
+	}
+	return &Metrics{opts: opts}
+}
+
+// Observe counts the error in DefaultMetrics.
+func Observe(err error) {
+	DefaultMetrics.Observe(err)
+}
+
+// MetricsHandler returns the http.Handler serving
+// DefaultMetrics.
+func MetricsHandler() http.Handler {
+	return DefaultMetrics
+}
+
+// Observe increments the counter for the code, outermost
+// operation and severity of the error. If err is nil,
+// Observe is a no-op.
+func (m *Metrics) Observe(err error) {
+	if err == nil {
+		return
+	}
+	key := metricKey{
+		code:      Code(err).String(),
+		operation: m.operation(Operation(err)),
+		severity:  SeverityOf(err).String(),
+	}
+	c, ok := m.counters.Load(key)
+	if !ok {
+		c, _ = m.counters.LoadOrStore(key, new(atomic.Uint64))
+	}
+	c.(*atomic.Uint64).Add(1)
+}
+
+// Count returns the amount of errors observed with the
+// given labels.
+func (m *Metrics) Count(code ErrorCode, op string, s Severity) uint64 {
+	c, ok := m.counters.Load(metricKey{code: code.String(), operation: op, severity: s.String()})
+	if !ok {
+		return 0
+	}
+	return c.(*atomic.Uint64).Load()
+}
+
+// ServeHTTP implements http.Handler by writing the counters
+// in the Prometheus text exposition format.
+func (m *Metrics) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	type line struct {
+		labels string
+		value  uint64
+	}
+	var lines []line
+	m.counters.Range(func(k, v any) bool {
+		key := k.(metricKey)
+		lines = append(lines, line{
+			labels: `code="` + escapeLabel(key.code) +
+				`",operation="` + escapeLabel(key.operation) +
+				`",severity="` + escapeLabel(key.severity) + `"`,
+			value: v.(*atomic.Uint64).Load(),
+		})
+		return true
+	})
+	sort.Slice(lines, func(i, j int) bool {
+		return lines[i].labels < lines[j].labels
+	})
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	buf := bufio.NewWriter(w)
+	buf.WriteString("# HELP " + m.opts.Name + " Total number of application errors observed.\n")
+	buf.WriteString("# TYPE " + m.opts.Name + " counter\n")
+	for _, l := range lines {
+		buf.WriteString(m.opts.Name + "{" + l.labels + "} " + strconv.FormatUint(l.value, 10) + "\n")
+	}
+	buf.Flush() //nolint:errcheck
+}
+
+// HandlerFunc is an http.HandlerFunc that returns an error.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+// Middleware returns an http.Handler that calls fn and
+// observes any error returned. The error is written to the
+// response with WriteHTTP.
+func (m *Metrics) Middleware(fn HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		err := fn(w, r)
+		if err == nil {
+			return
+		}
+		m.Observe(err)
+		WriteHTTP(w, err)
+	})
+}
+
+// operation returns the operation label for op, falling
+// back to OtherOperation once the maximum amount of
+// operations has been reached.
+func (m *Metrics) operation(op string) string {
+	if _, ok := m.operations.Load(op); ok {
+		return op
+	}
+	if m.opCount.Add(1) > int64(m.opts.MaxOperations) {
+		m.opCount.Add(-1)
+		return OtherOperation
+	}
+	if _, loaded := m.operations.LoadOrStore(op, struct{}{}); loaded {
+		m.opCount.Add(-1)
+	}
+	return op
+}
+
+// labelReplacer escapes label values for the Prometheus
+// text exposition format.
+var labelReplacer = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+// escapeLabel escapes a label value for the Prometheus
+// text exposition format.
+func escapeLabel(s string) string {
+	return labelReplacer.Replace(s)
+}
@@ -0,0 +1,163 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestMetrics_Observe(t *testing.T) {
+	m := NewMetrics(MetricsOptions{})
+	m.Observe(nil)
+	m.Observe(NewNotFound(nil, "message", "UserStore.Find"))
+	m.Observe(fmt.Errorf("wrap: %w", NewNotFound(nil, "message", "UserStore.Find")))
+	m.Observe(NewInternal(nil, "message", "UserStore.Find").WithSeverity(SeverityCritical))
+
+	tt := map[string]struct {
+		code     ErrorCode
+		severity Severity
+		want     uint64
+	}{
+		"Not Found": {NOTFOUND, SeverityInfo, 2},
+		"Critical":  {INTERNAL, SeverityCritical, 1},
+		"Missing":   {INVALID, SeverityInfo, 0},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := m.Count(test.code, "UserStore.Find", test.severity)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %d, got %d", test.want, got)
+			}
+		})
+	}
+}
+
+func TestMetrics_Concurrent(t *testing.T) {
+	m := NewMetrics(MetricsOptions{})
+	var wg sync.WaitGroup
+	for i := 0; i < 50; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				m.Observe(NewInvalid(nil, "message", "op"))
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := m.Count(INVALID, "op", SeverityInfo); got != 5000 {
+		t.Fatalf("expecting 5000, got %d", got)
+	}
+}
+
+func TestMetrics_MaxOperations(t *testing.T) {
+	m := NewMetrics(MetricsOptions{MaxOperations: 2})
+	for _, op := range []string{"a", "b", "c", "d", "a"} {
+		m.Observe(NewInternal(nil, "message", op))
+	}
+
+	tt := map[string]uint64{
+		"a":            2,
+		"b":            1,
+		"c":            0,
+		OtherOperation: 2,
+	}
+	for op, want := range tt {
+		if got := m.Count(INTERNAL, op, SeverityError); got != want {
+			t.Fatalf("expecting %d for %s, got %d", want, op, got)
+		}
+	}
+}
+
+func TestMetrics_ServeHTTP(t *testing.T) {
+	m := NewMetrics(MetricsOptions{})
+	m.Observe(NewNotFound(nil, "message", "UserStore.Find"))
+	m.Observe(NewNotFound(nil, "message", "UserStore.Find"))
+	m.Observe(NewInternal(nil, "message", `quote"op`))
+
+	server := httptest.NewServer(m)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL)
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+
+	want := `# HELP errors_total Total number of application errors observed.
+# TYPE errors_total counter
+errors_total{code="internal",operation="quote\"op",severity="error"} 1
+errors_total{code="not_found",operation="UserStore.Find",severity="info"} 2
+`
+	if !reflect.DeepEqual(want, string(body)) {
+		t.Fatalf("expecting %s, got %s", want, string(body))
+	}
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("expecting prometheus content type, got %s", ct)
+	}
+}
+
+func TestMetrics_Middleware(t *testing.T) {
+	tt := map[string]struct {
+		input  error
+		status int
+		count  uint64
+	}{
+		"Success": {
+			nil,
+			http.StatusOK,
+			0,
+		},
+		"Error": {
+			NewNotFound(nil, "User not found", "op"),
+			http.StatusNotFound,
+			1,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			m := NewMetrics(MetricsOptions{})
+			handler := m.Middleware(func(w http.ResponseWriter, r *http.Request) error {
+				return test.input
+			})
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/", nil))
+			if rr.Code != test.status {
+				t.Fatalf("expecting %d, got %d", test.status, rr.Code)
+			}
+			if got := m.Count(NOTFOUND, "op", SeverityInfo); got != test.count {
+				t.Fatalf("expecting %d, got %d", test.count, got)
+			}
+		})
+	}
+}
+
+func TestObserve(t *testing.T) {
+	orig := DefaultMetrics
+	t.Cleanup(func() { DefaultMetrics = orig })
+	DefaultMetrics = NewMetrics(MetricsOptions{})
+
+	Observe(NewConflict(nil, "message", "op"))
+	if got := DefaultMetrics.Count(CONFLICT, "op", SeverityWarn); got != 1 {
+		t.Fatalf("expecting 1, got %d", got)
+	}
+	if MetricsHandler() != DefaultMetrics {
+		t.Fatal("expecting handler to be the default metrics")
+	}
+}
@@ -0,0 +1,12 @@
+module github.com/ainsleyclark/errors/mysqlerr
+
+go 1.24.0
+
+require (
+	github.com/ainsleyclark/errors v0.0.0
+	github.com/go-sql-driver/mysql v1.10.1
+)
+
+require filippo.io/edwards25519 v1.2.0 // indirect
+
+replace github.com/ainsleyclark/errors => ../
@@ -0,0 +1,144 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package mysqlerr converts MySQL driver errors into
+// application errors, using the error number to determine
+// the error code.
+package mysqlerr
+
+import (
+	"database/sql"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/ainsleyclark/errors"
+	"github.com/go-sql-driver/mysql"
+)
+
+// Metadata keys set on errors returned by FromError.
+const (
+	// NumberKey is the error number returned by MySQL.
+	NumberKey = "mysql_errno"
+	// ConstraintKey is the name of the violated constraint
+	// or key.
+	ConstraintKey = "constraint"
+	// TableKey is the name of the table.
+	TableKey = "table"
+	// ColumnKey is the name of the column.
+	ColumnKey = "column"
+)
+
+// MySQL server error numbers for integrity constraint
+// violations.
+// See https://dev.mysql.com/doc/mysql-errors/8.0/en/server-error-reference.html
+const (
+	ErDupKey                  = 1022
+	ErBadNullError            = 1048
+	ErDupEntry                = 1062
+	ErNoReferencedRow         = 1216
+	ErRowIsReferenced         = 1217
+	ErRowIsReferenced2        = 1451
+	ErNoReferencedRow2        = 1452
+	ErDupEntryWithKeyName     = 1586
+	ErCheckConstraintViolated = 3819
+)
+
+var (
+	// dupEntry matches "Duplicate entry 'a' for key 'users.email'".
+	dupEntry = regexp.MustCompile(`for key '([^']+)'`)
+	// badNull matches "Column 'email' cannot be null".
+	badNull = regexp.MustCompile(`Column '([^']+)' cannot be null`)
+	// foreignKey matches "(`db`.`orders`, CONSTRAINT `fk` FOREIGN KEY (`user_id`)".
+	foreignKey = regexp.MustCompile("`([^`]+)`, CONSTRAINT `([^`]+)` FOREIGN KEY \\(`([^`]+)`\\)")
+	// checkConstraint matches "Check constraint 'chk' is violated".
+	checkConstraint = regexp.MustCompile(`Check constraint '([^']+)' is violated`)
+)
+
+// FromError returns an Error with the code determined by
+// Classify, falling back to INTERNAL. The error number and
+// the constraint, table and column parsed from the error
+// message are stored in the metadata.
+// If err is nil, FromError returns nil.
+func FromError(err error, message, op string) *errors.Error {
+	if err == nil {
+		return nil
+	}
+
+	code := Classify(err)
+	if code == "" {
+		code = errors.INTERNAL
+	}
+
+	e := errors.NewDepth(1, err, message, code, op)
+
+	var myErr *mysql.MySQLError
+	if !errors.As(err, &myErr) {
+		return e
+	}
+
+	e.WithMeta(NumberKey, strconv.Itoa(int(myErr.Number)))
+	for k, v := range parse(myErr) {
+		if v != "" {
+			e.WithMeta(k, v)
+		}
+	}
+
+	return e
+}
+
+// Classify returns the error code for MySQL errors, or an
+// empty string if the error is not recognised. It can be
+// passed to errors.RegisterClassifier.
+//
+//   - No rows returns NOTFOUND.
+//   - Duplicate entries return CONFLICT.
+//   - Check, not null and foreign key violations return INVALID.
+func Classify(err error) errors.ErrorCode {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.NOTFOUND
+	}
+	var myErr *mysql.MySQLError
+	if !errors.As(err, &myErr) {
+		return ""
+	}
+	switch myErr.Number {
+	case ErDupKey, ErDupEntry, ErDupEntryWithKeyName:
+		return errors.CONFLICT
+	case ErBadNullError, ErNoReferencedRow, ErRowIsReferenced, ErRowIsReferenced2,
+		ErNoReferencedRow2, ErCheckConstraintViolated:
+		return errors.INVALID
+	}
+	return ""
+}
+
+// parse obtains the constraint, table and column from the
+// error message, as MySQL doesn't return them separately.
+func parse(err *mysql.MySQLError) map[string]string {
+	meta := make(map[string]string)
+	switch err.Number {
+	case ErDupKey, ErDupEntry, ErDupEntryWithKeyName:
+		if m := dupEntry.FindStringSubmatch(err.Message); m != nil {
+			// MySQL 8 prefixes the key with the table name.
+			if table, key, ok := strings.Cut(m[1], "."); ok {
+				meta[TableKey], meta[ConstraintKey] = table, key
+			} else {
+				meta[ConstraintKey] = m[1]
+			}
+		}
+	case ErBadNullError:
+		if m := badNull.FindStringSubmatch(err.Message); m != nil {
+			meta[ColumnKey] = m[1]
+		}
+	case ErNoReferencedRow, ErRowIsReferenced, ErRowIsReferenced2, ErNoReferencedRow2:
+		if m := foreignKey.FindStringSubmatch(err.Message); m != nil {
+			meta[TableKey], meta[ConstraintKey], meta[ColumnKey] = m[1], m[2], m[3]
+		}
+	case ErCheckConstraintViolated:
+		if m := checkConstraint.FindStringSubmatch(err.Message); m != nil {
+			meta[ConstraintKey] = m[1]
+		}
+	}
+	return meta
+}
@@ -0,0 +1,115 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package mysqlerr
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ainsleyclark/errors"
+	"github.com/go-sql-driver/mysql"
+)
+
+func TestClassify(t *testing.T) {
+	tt := map[string]struct {
+		input error
+		want  errors.ErrorCode
+	}{
+		"No Rows":         {sql.ErrNoRows, errors.NOTFOUND},
+		"Duplicate":       {&mysql.MySQLError{Number: ErDupEntry}, errors.CONFLICT},
+		"Duplicate Key":   {&mysql.MySQLError{Number: ErDupKey}, errors.CONFLICT},
+		"Not Null":        {&mysql.MySQLError{Number: ErBadNullError}, errors.INVALID},
+		"Foreign Key":     {&mysql.MySQLError{Number: ErNoReferencedRow2}, errors.INVALID},
+		"Referenced":      {&mysql.MySQLError{Number: ErRowIsReferenced2}, errors.INVALID},
+		"Check":           {&mysql.MySQLError{Number: ErCheckConstraintViolated}, errors.INVALID},
+		"Wrapped":         {fmt.Errorf("wrap: %w", &mysql.MySQLError{Number: ErDupEntry}), errors.CONFLICT},
+		"Other Number":    {&mysql.MySQLError{Number: 1146}, ""},
+		"Unknown":         {fmt.Errorf("error"), ""},
+		"Driver Sentinel": {mysql.ErrInvalidConn, ""},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := Classify(test.input)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFromError(t *testing.T) {
+	tt := map[string]struct {
+		input error
+		code  errors.ErrorCode
+		meta  map[string]string
+	}{
+		"Duplicate MySQL 8": {
+			&mysql.MySQLError{Number: ErDupEntry, Message: "Duplicate entry 'a@b.com' for key 'users.email_unique'"},
+			errors.CONFLICT,
+			map[string]string{NumberKey: "1062", TableKey: "users", ConstraintKey: "email_unique"},
+		},
+		"Duplicate MySQL 5": {
+			&mysql.MySQLError{Number: ErDupEntry, Message: "Duplicate entry 'a@b.com' for key 'email_unique'"},
+			errors.CONFLICT,
+			map[string]string{NumberKey: "1062", ConstraintKey: "email_unique"},
+		},
+		"Not Null": {
+			&mysql.MySQLError{Number: ErBadNullError, Message: "Column 'email' cannot be null"},
+			errors.INVALID,
+			map[string]string{NumberKey: "1048", ColumnKey: "email"},
+		},
+		"Foreign Key": {
+			&mysql.MySQLError{
+				Number:  ErNoReferencedRow2,
+				Message: "Cannot add or update a child row: a foreign key constraint fails (`shop`.`orders`, CONSTRAINT `fk_user` FOREIGN KEY (`user_id`) REFERENCES `users` (`id`))",
+			},
+			errors.INVALID,
+			map[string]string{NumberKey: "1452", TableKey: "orders", ConstraintKey: "fk_user", ColumnKey: "user_id"},
+		},
+		"Check": {
+			&mysql.MySQLError{Number: ErCheckConstraintViolated, Message: "Check constraint 'age_check' is violated."},
+			errors.INVALID,
+			map[string]string{NumberKey: "3819", ConstraintKey: "age_check"},
+		},
+		"Internal": {
+			&mysql.MySQLError{Number: 1146, Message: "Table 'shop.users' doesn't exist"},
+			errors.INTERNAL,
+			map[string]string{NumberKey: "1146"},
+		},
+		"No Rows": {
+			sql.ErrNoRows,
+			errors.NOTFOUND,
+			map[string]string{},
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := FromError(test.input, "message", "op")
+			if !reflect.DeepEqual(test.code, got.Code) {
+				t.Fatalf("expecting %s, got %s", test.code, got.Code)
+			}
+			if !reflect.DeepEqual(test.meta, errors.Metadata(got)) {
+				t.Fatalf("expecting %v, got %v", test.meta, errors.Metadata(got))
+			}
+			if !errors.Is(got, test.input) {
+				t.Fatalf("expecting %s to wrap %s", got, test.input)
+			}
+			if !strings.Contains(got.FileLine(), "mysqlerr_test.go") {
+				t.Fatalf("expecting file line in test, got %s", got.FileLine())
+			}
+		})
+	}
+}
+
+func TestFromError_Nil(t *testing.T) {
+	if got := FromError(nil, "message", "op"); got != nil {
+		t.Fatalf("expecting nil, got %s", got)
+	}
+}
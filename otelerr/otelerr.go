@@ -0,0 +1,151 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package otelerr records application errors on
+// OpenTelemetry spans, preserving the error code,
+// operation, metadata and stacktrace.
+package otelerr
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/ainsleyclark/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Attribute keys used in addition to the semantic
+// conventions for exceptions.
+const (
+	// OperationKey is the attribute key for the operation
+	// of the error.
+	OperationKey = attribute.Key("error.operation")
+	// MessageKey is the attribute key for the human-readable
+	// message of the error.
+	MessageKey = attribute.Key("error.message")
+	// MetadataPrefix is prepended to each metadata key.
+	MetadataPrefix = "error.metadata."
+)
+
+// Option configures how an error is recorded.
+type Option func(*config)
+
+type config struct {
+	kind    trace.SpanKind
+	options []trace.EventOption
+}
+
+// WithSpanKind overrides the kind of the span used for
+// determining the span status. By default, the kind is
+// obtained from the span if it's exposed by the
+// implementation.
+func WithSpanKind(kind trace.SpanKind) Option {
+	return func(c *config) {
+		c.kind = kind
+	}
+}
+
+// WithEventOptions appends options to the exception event,
+// such as trace.WithTimestamp.
+func WithEventOptions(opts ...trace.EventOption) Option {
+	return func(c *config) {
+		c.options = append(c.options, opts...)
+	}
+}
+
+// RecordError records the error on the span within the
+// context. See Record for more details.
+func RecordError(ctx context.Context, err error, opts ...Option) {
+	Record(trace.SpanFromContext(ctx), err, opts...)
+}
+
+// Record adds an exception event to the span with the
+// exception type set to the error code, the stacktrace
+// obtained from the error's program counters and the
+// operation and metadata as attributes.
+//
+// The span status is set to Error unless the code
+// describes a client error (4xx) and the span is a server
+// span, in line with the OpenTelemetry HTTP conventions.
+// If err is nil or the span is not recording, Record is a
+// no-op.
+func Record(span trace.Span, err error, opts ...Option) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+
+	c := config{kind: spanKind(span)}
+	for _, opt := range opts {
+		opt(&c)
+	}
+
+	code := errors.Code(err)
+	attrs := []attribute.KeyValue{
+		semconv.ExceptionType(code.String()),
+		semconv.ExceptionMessage(err.Error()),
+		MessageKey.String(errors.Message(err)),
+	}
+	if op := errors.Operation(err); op != "" {
+		attrs = append(attrs, OperationKey.String(op))
+	}
+	if st := stacktrace(err); st != "" {
+		attrs = append(attrs, semconv.ExceptionStacktrace(st))
+	}
+	for k, v := range errors.Metadata(err) {
+		attrs = append(attrs, attribute.String(MetadataPrefix+k, v))
+	}
+
+	span.AddEvent(semconv.ExceptionEventName, append(c.options, trace.WithAttributes(attrs...))...)
+
+	if IsSpanError(code, c.kind) {
+		span.SetStatus(codes.Error, errors.Message(err))
+	}
+}
+
+// IsSpanError reports whether an error with the given code
+// should mark a span of the given kind as failed. Client
+// errors (4xx) are not considered failures for server spans
+// as the server behaved correctly.
+func IsSpanError(code errors.ErrorCode, kind trace.SpanKind) bool {
+	if kind != trace.SpanKindServer {
+		return true
+	}
+	return code.HTTPStatus() >= http.StatusInternalServerError
+}
+
+// spanKind returns the kind of the span if the
+// implementation exposes it, such as the SDK's
+// ReadOnlySpan.
+func spanKind(span trace.Span) trace.SpanKind {
+	if s, ok := span.(interface{ SpanKind() trace.SpanKind }); ok {
+		return s.SpanKind()
+	}
+	return trace.SpanKindUnspecified
+}
+
+// stacktrace returns the stacktrace of the innermost Error
+// within the chain, formatted in the same manner as a Go
+// panic. File paths are rendered according to the
+// configured path mode.
+func stacktrace(err error) string {
+	var frames []errors.Frame
+	for ; err != nil; err = errors.Unwrap(err) {
+		if e, ok := err.(*errors.Error); ok {
+			if f := e.Frames(); len(f) > 0 {
+				frames = f
+			}
+		}
+	}
+
+	var sb strings.Builder
+	for _, frame := range frames {
+		sb.WriteString(frame.Function + "()\n\t" + frame.File + ":" + strconv.Itoa(frame.Line) + "\n")
+	}
+	return sb.String()
+}
@@ -0,0 +1,149 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package otelerr
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ainsleyclark/errors"
+	"github.com/ainsleyclark/errors/errorstest"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func record(t *testing.T, kind trace.SpanKind, err error, opts ...Option) sdktrace.ReadOnlySpan {
+	t.Helper()
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	ctx, span := provider.Tracer("test").Start(context.Background(), "span", trace.WithSpanKind(kind))
+	RecordError(ctx, err, opts...)
+	span.End()
+	spans := exporter.GetSpans().Snapshots()
+	if len(spans) != 1 {
+		t.Fatalf("expecting 1 span, got %d", len(spans))
+	}
+	return spans[0]
+}
+
+func attributes(kvs []attribute.KeyValue) map[string]string {
+	m := make(map[string]string, len(kvs))
+	for _, kv := range kvs {
+		m[string(kv.Key)] = kv.Value.Emit()
+	}
+	return m
+}
+
+func TestRecordError(t *testing.T) {
+	err := errors.NewNotFound(fmt.Errorf("sql: no rows"), "User not found", "UserStore.Find").WithMeta("id", "1")
+	span := record(t, trace.SpanKindInternal, err)
+
+	events := span.Events()
+	if len(events) != 1 {
+		t.Fatalf("expecting 1 event, got %d", len(events))
+	}
+	if events[0].Name != "exception" {
+		t.Fatalf("expecting exception, got %s", events[0].Name)
+	}
+
+	got := attributes(events[0].Attributes)
+	want := map[string]string{
+		"exception.type":    errors.NOTFOUND.String(),
+		"exception.message": err.Error(),
+		"error.message":     "User not found",
+		"error.operation":   "UserStore.Find",
+		"error.metadata.id": "1",
+	}
+	for k, v := range want {
+		if !reflect.DeepEqual(v, got[k]) {
+			t.Fatalf("expecting %s for %s, got %s", v, k, got[k])
+		}
+	}
+	if !strings.HasPrefix(got["exception.stacktrace"], "github.com/ainsleyclark/errors/otelerr.TestRecordError()") {
+		t.Fatalf("expecting stacktrace to start with test function, got %s", got["exception.stacktrace"])
+	}
+	if span.Status().Code != codes.Error || span.Status().Description != "User not found" {
+		t.Fatalf("expecting error status, got %+v", span.Status())
+	}
+}
+
+func TestRecordError_Status(t *testing.T) {
+	tt := map[string]struct {
+		kind trace.SpanKind
+		err  error
+		opts []Option
+		want codes.Code
+	}{
+		"Server Not Found": {
+			trace.SpanKindServer,
+			errors.NewNotFound(nil, "message", "op"),
+			nil,
+			codes.Unset,
+		},
+		"Server Internal": {
+			trace.SpanKindServer,
+			errors.NewInternal(nil, "message", "op"),
+			nil,
+			codes.Error,
+		},
+		"Client Not Found": {
+			trace.SpanKindClient,
+			errors.NewNotFound(nil, "message", "op"),
+			nil,
+			codes.Error,
+		},
+		"Overridden Kind": {
+			trace.SpanKindInternal,
+			errors.NewInvalid(nil, "message", "op"),
+			[]Option{WithSpanKind(trace.SpanKindServer)},
+			codes.Unset,
+		},
+		"Standard Error": {
+			trace.SpanKindServer,
+			fmt.Errorf("error"),
+			nil,
+			codes.Error,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := record(t, test.kind, test.err, test.opts...).Status().Code
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestRecordError_Nil(t *testing.T) {
+	span := record(t, trace.SpanKindServer, nil)
+	if len(span.Events()) != 0 {
+		t.Fatalf("expecting no events, got %d", len(span.Events()))
+	}
+}
+
+func TestRecord_NonRecording(t *testing.T) {
+	// Must not panic with the no-op span.
+	Record(trace.SpanFromContext(context.Background()), errors.NewInternal(nil, "message", "op"))
+}
+
+func TestRecordError_StacktracePaths(t *testing.T) {
+	errorstest.WithConfig(t, errors.Config{FilePaths: errors.PathBase})
+	err := errors.NewInternal(fmt.Errorf("err"), "message", "op")
+	got := attributes(record(t, trace.SpanKindInternal, err).Events()[0].Attributes)["exception.stacktrace"]
+	if !strings.Contains(got, "\n\totelerr_test.go:") {
+		t.Fatalf("expecting base file paths, got %s", got)
+	}
+	if strings.Contains(got, "/otelerr_test.go:") {
+		t.Fatalf("expecting no absolute paths, got %s", got)
+	}
+}
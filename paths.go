@@ -0,0 +1,117 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"path"
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+)
+
+// PathMode defines how file paths are rendered within file
+// lines and stacktraces.
+type PathMode int
+
+// Path rendering modes.
+const (
+	// PathFull - The absolute path the binary was built
+	// with, such as "/Users/me/project/store/users.go".
+	PathFull PathMode = iota
+	// PathModule - The path relative to the main module,
+	// such as "store/users.go". Files outside of the main
+	// module are rendered with their package import path,
+	// such as "github.com/lib/pq/conn.go".
+	PathModule
+	// PathBase - The file name only, such as "users.go".
+	PathBase
+	// PathTrim - The path with the first matching prefix
+	// of Config.TrimPrefixes removed.
+	PathTrim
+)
+
+var (
+	mainModuleOnce sync.Once
+	mainModule     string
+)
+
+// renderPath renders the file according to the path mode. The
+// function the file belongs to is used for determining the
+// package when rendering module paths.
+func renderPath(file, function string) string {
+	cfg := loadConfig()
+	switch cfg.FilePaths {
+	case PathModule:
+		return modulePath(file, function)
+	case PathBase:
+		return filepath.Base(file)
+	case PathTrim:
+		for _, prefix := range cfg.TrimPrefixes {
+			if prefix != "" && strings.HasPrefix(file, prefix) {
+				return strings.TrimLeft(strings.TrimPrefix(file, prefix), "/\\")
+			}
+		}
+	}
+	return file
+}
+
+// renderFileLine renders the path of a "file:line" string,
+// using the first program counter to obtain the function.
+func renderFileLine(fileLine string, pcs []uintptr) string {
+	mode := loadConfig().FilePaths
+	if fileLine == "" || mode == PathFull {
+		return fileLine
+	}
+	file, line := fileLine, ""
+	if i := strings.LastIndex(fileLine, ":"); i >= 0 {
+		file, line = fileLine[:i], fileLine[i:]
+	}
+	var function string
+	if mode == PathModule && len(pcs) > 0 {
+		frame, _ := runtime.CallersFrames(pcs[:1]).Next()
+		function = frame.Function
+	}
+	return renderPath(file, function) + line
+}
+
+// modulePath returns the file relative to the main module,
+// or prefixed with its package import path if it's outside
+// the main module. If the package can't be determined, the
+// base name of absolute paths is returned, relative paths
+// are assumed to have been rendered already.
+func modulePath(file, function string) string {
+	base := filepath.Base(file)
+	pkg := packagePath(function)
+	if pkg == "" || pkg == "main" {
+		if !filepath.IsAbs(file) {
+			return file
+		}
+		return base
+	}
+	mainModuleOnce.Do(func() {
+		if info, ok := debug.ReadBuildInfo(); ok {
+			mainModule = info.Main.Path
+		}
+	})
+	if mainModule != "" && (pkg == mainModule || strings.HasPrefix(pkg, mainModule+"/")) {
+		rel := strings.TrimPrefix(strings.TrimPrefix(pkg, mainModule), "/")
+		return path.Join(rel, base)
+	}
+	return pkg + "/" + base
+}
+
+// packagePath returns the import path of the package the
+// fully qualified function belongs to, for example
+// "github.com/lib/pq" for "github.com/lib/pq.(*conn).Query".
+func packagePath(function string) string {
+	slash := strings.LastIndex(function, "/")
+	dot := strings.Index(function[slash+1:], ".")
+	if dot < 0 {
+		return ""
+	}
+	return function[:slash+1+dot]
+}
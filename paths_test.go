@@ -0,0 +1,118 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"encoding/json"
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestError_FileLinePaths(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	e := NewInternal(nil, "message", "op")
+	line := e.fileLine[strings.LastIndex(e.fileLine, ":"):]
+
+	tt := map[string]struct {
+		mode     PathMode
+		prefixes []string
+		want     string
+	}{
+		"Full":      {PathFull, nil, wd + "/paths_test.go" + line},
+		"Module":    {PathModule, nil, "paths_test.go" + line},
+		"Base":      {PathBase, nil, "paths_test.go" + line},
+		"Trim":      {PathTrim, []string{"/nope", wd}, "paths_test.go" + line},
+		"No Prefix": {PathTrim, []string{"/nope"}, wd + "/paths_test.go" + line},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			withConfig(t, func(cfg *Config) { cfg.FilePaths, cfg.TrimPrefixes = test.mode, test.prefixes })
+			if got := e.FileLine(); !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+			if !strings.Contains(e.Error(), " "+test.want+" - ") {
+				t.Fatalf("expecting %s to contain %s", e.Error(), test.want)
+			}
+			buf, _ := json.Marshal(NewInternal(e, "message", "op"))
+			if !strings.Contains(string(buf), `\u003e `+test.want) {
+				t.Fatalf("expecting %s to contain %s", buf, test.want)
+			}
+		})
+	}
+}
+
+func TestModulePath(t *testing.T) {
+	tt := map[string]struct {
+		file     string
+		function string
+		want     string
+	}{
+		"Main Module Root": {
+			"/home/me/errors/errors.go",
+			"github.com/ainsleyclark/errors.New",
+			"errors.go",
+		},
+		"Main Module Package": {
+			"/home/me/errors/errorstest/golden.go",
+			"github.com/ainsleyclark/errors/errorstest.AssertGolden",
+			"errorstest/golden.go",
+		},
+		"Dependency": {
+			"/root/go/pkg/mod/github.com/lib/pq@v1.10.0/conn.go",
+			"github.com/lib/pq.(*conn).Query",
+			"github.com/lib/pq/conn.go",
+		},
+		"Standard Library": {
+			"/usr/local/go/src/net/http/server.go",
+			"net/http.(*conn).serve",
+			"net/http/server.go",
+		},
+		"Main Package": {
+			"/home/me/cmd/main.go",
+			"main.main",
+			"main.go",
+		},
+		"Unknown Relative": {
+			"store/users.go",
+			"",
+			"store/users.go",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := modulePath(test.file, test.function)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestError_StackTracePaths(t *testing.T) {
+	withConfig(t, func(cfg *Config) { cfg.FilePaths = PathModule })
+	e := NewInternal(nil, "message", "op")
+
+	if !strings.Contains(e.StackTrace(), "\tpaths_test.go:") {
+		t.Fatalf("expecting module relative paths, got %s", e.StackTrace())
+	}
+	if got := e.StackTraceSlice()[1]; !strings.HasPrefix(got, "paths_test.go:") {
+		t.Fatalf("expecting module relative path, got %s", got)
+	}
+	if got := e.Frames()[0].File; got != "paths_test.go" {
+		t.Fatalf("expecting paths_test.go, got %s", got)
+	}
+
+	restored := (&Error{}).WithFrames([]Frame{{Function: "github.com/ainsleyclark/errors/store.Find", File: "/home/me/errors/store/users.go"}})
+	if got := restored.Frames()[0].File; got != "store/users.go" {
+		t.Fatalf("expecting store/users.go, got %s", got)
+	}
+}
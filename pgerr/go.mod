@@ -0,0 +1,17 @@
+module github.com/ainsleyclark/errors/pgerr
+
+go 1.25.0
+
+require (
+	github.com/ainsleyclark/errors v0.0.0
+	github.com/jackc/pgx/v5 v5.11.0
+	github.com/lib/pq v1.12.3
+)
+
+require (
+	github.com/jackc/pgpassfile v1.0.0 // indirect
+	github.com/jackc/pgservicefile v0.0.0-20240606120523-5a60cdf6a761 // indirect
+	golang.org/x/text v0.29.0 // indirect
+)
+
+replace github.com/ainsleyclark/errors => ../
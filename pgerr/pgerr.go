@@ -0,0 +1,123 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package pgerr converts Postgres driver errors from
+// lib/pq and pgx into application errors, using the
+// SQLSTATE to determine the error code.
+package pgerr
+
+import (
+	"database/sql"
+
+	"github.com/ainsleyclark/errors"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+// Metadata keys set on errors returned by FromError.
+const (
+	// SQLStateKey is the SQLSTATE returned by Postgres.
+	SQLStateKey = "sqlstate"
+	// ConstraintKey is the name of the violated constraint.
+	ConstraintKey = "constraint"
+	// TableKey is the name of the table.
+	TableKey = "table"
+	// ColumnKey is the name of the column.
+	ColumnKey = "column"
+)
+
+// SQLSTATE codes for integrity constraint violations.
+// See https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	NotNullViolation    = "23502"
+	ForeignKeyViolation = "23503"
+	UniqueViolation     = "23505"
+	CheckViolation      = "23514"
+	ExclusionViolation  = "23P01"
+)
+
+// FromError returns an Error with the code determined by
+// Classify, falling back to INTERNAL. The SQLSTATE,
+// constraint, table and column reported by the driver are
+// stored in the metadata.
+// If err is nil, FromError returns nil.
+func FromError(err error, message, op string) *errors.Error {
+	if err == nil {
+		return nil
+	}
+
+	code := Classify(err)
+	if code == "" {
+		code = errors.INTERNAL
+	}
+
+	e := errors.NewDepth(1, err, message, code, op)
+	d := detailsOf(err)
+	for k, v := range map[string]string{
+		SQLStateKey:   d.state,
+		ConstraintKey: d.constraint,
+		TableKey:      d.table,
+		ColumnKey:     d.column,
+	} {
+		if v != "" {
+			e.WithMeta(k, v)
+		}
+	}
+
+	return e
+}
+
+// Classify returns the error code for Postgres errors, or an
+// empty string if the error is not recognised. It can be
+// passed to errors.RegisterClassifier.
+//
+//   - No rows returns NOTFOUND.
+//   - Unique and exclusion violations return CONFLICT.
+//   - Check, not null and foreign key violations return INVALID.
+func Classify(err error) errors.ErrorCode {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.NOTFOUND
+	}
+	switch detailsOf(err).state {
+	case UniqueViolation, ExclusionViolation:
+		return errors.CONFLICT
+	case CheckViolation, NotNullViolation, ForeignKeyViolation:
+		return errors.INVALID
+	}
+	return ""
+}
+
+// details are the fields shared by the driver errors.
+type details struct {
+	state      string
+	constraint string
+	table      string
+	column     string
+}
+
+// detailsOf returns the details of the first driver error
+// within the chain.
+func detailsOf(err error) details {
+	var (
+		pqErr  *pq.Error
+		pgxErr *pgconn.PgError
+	)
+	switch {
+	case errors.As(err, &pgxErr):
+		return details{
+			state:      pgxErr.Code,
+			constraint: pgxErr.ConstraintName,
+			table:      pgxErr.TableName,
+			column:     pgxErr.ColumnName,
+		}
+	case errors.As(err, &pqErr):
+		return details{
+			state:      string(pqErr.Code),
+			constraint: pqErr.Constraint,
+			table:      pqErr.Table,
+			column:     pqErr.Column,
+		}
+	}
+	return details{}
+}
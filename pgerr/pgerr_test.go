@@ -0,0 +1,100 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package pgerr
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ainsleyclark/errors"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/lib/pq"
+)
+
+func TestClassify(t *testing.T) {
+	tt := map[string]struct {
+		input error
+		want  errors.ErrorCode
+	}{
+		"SQL No Rows":     {sql.ErrNoRows, errors.NOTFOUND},
+		"PGX No Rows":     {pgx.ErrNoRows, errors.NOTFOUND},
+		"PGX Unique":      {&pgconn.PgError{Code: UniqueViolation}, errors.CONFLICT},
+		"PGX Exclusion":   {&pgconn.PgError{Code: ExclusionViolation}, errors.CONFLICT},
+		"PGX Check":       {&pgconn.PgError{Code: CheckViolation}, errors.INVALID},
+		"PGX Not Null":    {&pgconn.PgError{Code: NotNullViolation}, errors.INVALID},
+		"PGX Foreign Key": {&pgconn.PgError{Code: ForeignKeyViolation}, errors.INVALID},
+		"PQ Unique":       {&pq.Error{Code: UniqueViolation}, errors.CONFLICT},
+		"PQ Not Null":     {&pq.Error{Code: NotNullViolation}, errors.INVALID},
+		"Wrapped":         {fmt.Errorf("wrap: %w", &pq.Error{Code: UniqueViolation}), errors.CONFLICT},
+		"Other State":     {&pgconn.PgError{Code: "42P01"}, ""},
+		"Unknown":         {fmt.Errorf("error"), ""},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := Classify(test.input)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFromError(t *testing.T) {
+	tt := map[string]struct {
+		input error
+		code  errors.ErrorCode
+		meta  map[string]string
+	}{
+		"PGX": {
+			&pgconn.PgError{Code: UniqueViolation, ConstraintName: "users_email_key", TableName: "users", ColumnName: "email"},
+			errors.CONFLICT,
+			map[string]string{SQLStateKey: UniqueViolation, ConstraintKey: "users_email_key", TableKey: "users", ColumnKey: "email"},
+		},
+		"PQ": {
+			&pq.Error{Code: CheckViolation, Constraint: "age_check", Table: "users"},
+			errors.INVALID,
+			map[string]string{SQLStateKey: CheckViolation, ConstraintKey: "age_check", TableKey: "users"},
+		},
+		"No Rows": {
+			pgx.ErrNoRows,
+			errors.NOTFOUND,
+			map[string]string{},
+		},
+		"Internal": {
+			&pgconn.PgError{Code: "42P01", TableName: "users"},
+			errors.INTERNAL,
+			map[string]string{SQLStateKey: "42P01", TableKey: "users"},
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := FromError(test.input, "message", "op")
+			if !reflect.DeepEqual(test.code, got.Code) {
+				t.Fatalf("expecting %s, got %s", test.code, got.Code)
+			}
+			if !reflect.DeepEqual(test.meta, errors.Metadata(got)) {
+				t.Fatalf("expecting %v, got %v", test.meta, errors.Metadata(got))
+			}
+			if !errors.Is(got, test.input) {
+				t.Fatalf("expecting %s to wrap %s", got, test.input)
+			}
+			if !strings.Contains(got.FileLine(), "pgerr_test.go") {
+				t.Fatalf("expecting file line in test, got %s", got.FileLine())
+			}
+		})
+	}
+}
+
+func TestFromError_Nil(t *testing.T) {
+	if got := FromError(nil, "message", "op"); got != nil {
+		t.Fatalf("expecting nil, got %s", got)
+	}
+}
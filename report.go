@@ -0,0 +1,296 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Event levels, named after the levels used by most error
+// tracking services.
+const (
+	// LevelDebug - Diagnostic information.
+	LevelDebug = "debug"
+	// LevelInfo - Expected behaviour such as a missing entity.
+	LevelInfo = "info"
+	// LevelWarning - Client errors worth keeping an eye on.
+	LevelWarning = "warning"
+	// LevelError - Failures within the application.
+	LevelError = "error"
+	// LevelFatal - Failures that require immediate attention.
+	LevelFatal = "fatal"
+)
+
+// Reporter defines the method used for sending events to an
+// error tracking service, log or any other destination.
+type Reporter interface {
+	Report(ctx context.Context, event *Event) error
+}
+
+// Event is a reporter agnostic representation of an error,
+// built from the error chain by NewEvent.
+type Event struct {
+	// A unique, random identifier for the event.
+	ID string `json:"event_id"`
+	// The time in which the event was created.
+	Timestamp time.Time `json:"timestamp"`
+	// The severity of the error, obtained from SeverityOf.
+	Severity Severity `json:"-"`
+	// The level of the event, derived from the severity.
+	Level string `json:"level"`
+	// The ID of the error chain, see ID.
+	ErrorID string `json:"error_id,omitempty"`
+	// The time in which the outermost error was created.
+	Created time.Time `json:"created"`
+	// The elapsed duration recorded on the chain, see
+	// WithElapsed.
+	Elapsed time.Duration `json:"elapsed,omitempty"`
+	// The deployment that produced the event.
+	Environment Environment `json:"environment"`
+	// The application error code of the chain.
+	Code string `json:"code"`
+	// The full error string of the chain.
+	Message string `json:"message"`
+	// The outermost operation within the chain.
+	Operation string `json:"operation,omitempty"`
+	// Values used for grouping similar events together.
+	Fingerprint []string `json:"fingerprint,omitempty"`
+	// The merged metadata of the chain.
+	Tags map[string]string `json:"tags,omitempty"`
+	// Every error within the chain, ordered from the root
+	// cause outward.
+	Exceptions []Exception `json:"exceptions"`
+}
+
+// Exception describes a singular error within an
+// Event's chain.
+type Exception struct {
+	Type      string  `json:"type"`
+	Value     string  `json:"value"`
+	Operation string  `json:"operation,omitempty"`
+	Frames    []Frame `json:"frames,omitempty"`
+}
+
+// Frame describes a single function call in a stacktrace.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+var (
+	reportersMtx sync.RWMutex
+	reporters    []Reporter
+)
+
+// SetReporters replaces the reporters used by Report, calling
+// it with no arguments disables reporting.
+func SetReporters(r ...Reporter) {
+	reportersMtx.Lock()
+	defer reportersMtx.Unlock()
+	reporters = r
+}
+
+// Report builds an Event from the error and sends it to each
+// reporter set by SetReporters. Any errors returned by the
+// reporters are joined together.
+// If err is nil, Report returns nil.
+func Report(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	reportersMtx.RLock()
+	rs := reporters
+	reportersMtx.RUnlock()
+
+	if len(rs) == 0 {
+		return nil
+	}
+
+	event := NewEvent(err)
+	errs := make([]error, 0, len(rs))
+	for _, r := range rs {
+		errs = append(errs, r.Report(ctx, event))
+	}
+
+	return errors.Join(errs...)
+}
+
+// NewEvent converts the error chain into an Event, the level
+// is derived from the severity, tags are obtained from the
+// metadata and an Exception is added for each error within
+// the chain.
+// If err is nil, NewEvent returns nil.
+func NewEvent(err error) *Event {
+	if err == nil {
+		return nil
+	}
+
+	code := Code(err)
+	severity := SeverityOf(err)
+	event := &Event{
+		ID:          eventID(),
+		Timestamp:   time.Now().UTC(),
+		ErrorID:     ID(err),
+		Created:     created(err),
+		Environment: eventEnvironment(err),
+		Elapsed:     elapsed(err),
+		Severity:    severity,
+		Level:       eventLevel(severity),
+		Code:        code.String(),
+		Message:     err.Error(),
+		Operation:   Operation(err),
+		Fingerprint: []string{code.String()},
+		Tags:        Metadata(err),
+	}
+
+	for ; err != nil; err = errors.Unwrap(err) {
+		e, ok := err.(*Error)
+		if !ok {
+			event.Exceptions = append(event.Exceptions, Exception{
+				Type:  fmt.Sprintf("%T", err),
+				Value: err.Error(),
+			})
+			continue
+		}
+		if e.Operation != "" {
+			event.Fingerprint = append(event.Fingerprint, e.Operation)
+		}
+		event.Exceptions = append(event.Exceptions, Exception{
+			Type:      exceptionType(e),
+			Value:     e.Message,
+			Operation: e.Operation,
+			Frames:    e.Frames(),
+		})
+	}
+
+	// Reverse the exceptions so the root cause comes first.
+	for i, j := 0, len(event.Exceptions)-1; i < j; i, j = i+1, j-1 {
+		event.Exceptions[i], event.Exceptions[j] = event.Exceptions[j], event.Exceptions[i]
+	}
+
+	return event
+}
+
+// eventLevel returns the event level for the given severity.
+func eventLevel(s Severity) string {
+	switch s {
+	case SeverityDebug:
+		return LevelDebug
+	case SeverityInfo:
+		return LevelInfo
+	case SeverityWarn:
+		return LevelWarning
+	case SeverityCritical:
+		return LevelFatal
+	}
+	return LevelError
+}
+
+// exceptionType returns the code of the error, falling back
+// to the Go type if there is none.
+func exceptionType(e *Error) string {
+	if e.Code == "" {
+		return fmt.Sprintf("%T", e)
+	}
+	return e.Code.String()
+}
+
+// Frames returns the stacktrace of the error, with the most
+// recent call first. Errors decoded with UnmarshalBinary, or
+// restored with WithFrames, return the frames they were
+// created with.
+func (e *Error) Frames() []Frame {
+	if len(e.pcs) == 0 {
+		return e.renderFrames()
+	}
+	var (
+		frames  []Frame
+		rFrames = e.RuntimeFrames()
+	)
+	for {
+		frame, more := rFrames.Next()
+		if frame.Function != "" {
+			frames = append(frames, Frame{
+				Function: frame.Function,
+				File:     renderPath(frame.File, frame.Function),
+				Line:     frame.Line,
+			})
+		}
+		if !more {
+			break
+		}
+	}
+	return frames
+}
+
+// renderFrames returns the restored frames of the error
+// with their paths rendered according to Config.FilePaths.
+func (e *Error) renderFrames() []Frame {
+	if mode := loadConfig().FilePaths; len(e.stack) == 0 || mode == PathFull {
+		return e.stack
+	}
+	frames := make([]Frame, len(e.stack))
+	for i, f := range e.stack {
+		f.File = renderPath(f.File, f.Function)
+		frames[i] = f
+	}
+	return frames
+}
+
+// WithFrames sets the stacktrace frames of the error,
+// replacing any captured program counters, and returns the
+// error to allow for chaining. It's intended for restoring
+// errors decoded from other formats.
+func (e *Error) WithFrames(frames []Frame) *Error {
+	e.pcs = nil
+	e.stack = frames
+	return e
+}
+
+// eventID returns a random 32 character hex string.
+func eventID() string {
+	b := make([]byte, 16)
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// created returns the creation time of the outermost Error
+// within the chain.
+func created(err error) time.Time {
+	if e, ok := AsType[*Error](err); ok {
+		return e.time
+	}
+	return time.Time{}
+}
+
+// elapsed returns the outermost elapsed duration recorded
+// within the chain.
+func elapsed(err error) time.Duration {
+	for ; err != nil; err = Unwrap(err) {
+		if e, ok := err.(*Error); ok && e.elapsed != 0 {
+			return e.elapsed
+		}
+	}
+	return 0
+}
+
+// eventEnvironment returns the environment the outermost
+// Error was restored with, or the current environment.
+func eventEnvironment(err error) Environment {
+	if e, ok := AsType[*Error](err); ok {
+		if env, ok := e.Environment(); ok {
+			return env
+		}
+	}
+	return CurrentEnvironment()
+}
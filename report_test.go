@@ -0,0 +1,172 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+type stubReporter struct {
+	events []*Event
+	err    error
+}
+
+func (s *stubReporter) Report(_ context.Context, event *Event) error {
+	s.events = append(s.events, event)
+	return s.err
+}
+
+func TestNewEvent(t *testing.T) {
+	root := fmt.Errorf("sql: no rows")
+	inner := NewNotFound(root, "User not found", "UserStore.Find").WithMeta("id", "1")
+	outer := NewInternal(inner, "", "UserService.Get").WithMeta("id", "2").WithMeta("table", "users")
+
+	got := NewEvent(outer)
+
+	if len(got.ID) != 32 {
+		t.Fatalf("expecting 32 character id, got %s", got.ID)
+	}
+	if got.Timestamp.IsZero() {
+		t.Fatalf("expecting timestamp to be set")
+	}
+	if !reflect.DeepEqual(LevelError, got.Level) {
+		t.Fatalf("expecting %s, got %s", LevelError, got.Level)
+	}
+	if !reflect.DeepEqual(INTERNAL.String(), got.Code) {
+		t.Fatalf("expecting %s, got %s", INTERNAL, got.Code)
+	}
+	if !reflect.DeepEqual("UserService.Get", got.Operation) {
+		t.Fatalf("expecting UserService.Get, got %s", got.Operation)
+	}
+	wantFingerprint := []string{"internal", "UserService.Get", "UserStore.Find"}
+	if !reflect.DeepEqual(wantFingerprint, got.Fingerprint) {
+		t.Fatalf("expecting %v, got %v", wantFingerprint, got.Fingerprint)
+	}
+	wantTags := map[string]string{"id": "2", "table": "users"}
+	if !reflect.DeepEqual(wantTags, got.Tags) {
+		t.Fatalf("expecting %v, got %v", wantTags, got.Tags)
+	}
+
+	if len(got.Exceptions) != 3 {
+		t.Fatalf("expecting 3 exceptions, got %d", len(got.Exceptions))
+	}
+	wantTypes := []string{"*errors.errorString", "not_found", "internal"}
+	for i, ex := range got.Exceptions {
+		if !reflect.DeepEqual(wantTypes[i], ex.Type) {
+			t.Fatalf("expecting %s, got %s", wantTypes[i], ex.Type)
+		}
+	}
+	if len(got.Exceptions[0].Frames) != 0 {
+		t.Fatalf("expecting no frames for root cause, got %v", got.Exceptions[0].Frames)
+	}
+	frame := got.Exceptions[2].Frames[0]
+	if !reflect.DeepEqual("github.com/ainsleyclark/errors.TestNewEvent", frame.Function) {
+		t.Fatalf("expecting TestNewEvent, got %s", frame.Function)
+	}
+	if !strings.HasSuffix(frame.File, "report_test.go") {
+		t.Fatalf("expecting report_test.go, got %s", frame.File)
+	}
+}
+
+func TestNewEvent_Nil(t *testing.T) {
+	got := NewEvent(nil)
+	if got != nil {
+		t.Fatalf("expecting nil, got %+v", got)
+	}
+}
+
+func TestEventLevel(t *testing.T) {
+	tt := map[string]struct {
+		input Severity
+		want  string
+	}{
+		"Debug":    {SeverityDebug, LevelDebug},
+		"Info":     {SeverityInfo, LevelInfo},
+		"Warn":     {SeverityWarn, LevelWarning},
+		"Error":    {SeverityError, LevelError},
+		"Critical": {SeverityCritical, LevelFatal},
+		"Unset":    {0, LevelError},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := eventLevel(test.input)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	t.Cleanup(func() { SetReporters() })
+
+	tt := map[string]struct {
+		input     error
+		reporters []Reporter
+		want      int
+		err       string
+	}{
+		"Nil Error": {
+			nil,
+			[]Reporter{&stubReporter{}},
+			0,
+			"",
+		},
+		"No Reporters": {
+			NewInternal(nil, "message", "op"),
+			nil,
+			0,
+			"",
+		},
+		"Success": {
+			NewInternal(nil, "message", "op"),
+			[]Reporter{&stubReporter{}, &stubReporter{}},
+			1,
+			"",
+		},
+		"Reporter Error": {
+			NewInternal(nil, "message", "op"),
+			[]Reporter{&stubReporter{}, &stubReporter{err: fmt.Errorf("reporter error")}},
+			1,
+			"reporter error",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			SetReporters(test.reporters...)
+			err := Report(context.Background(), test.input)
+			if test.err != "" {
+				if err == nil || !strings.Contains(err.Error(), test.err) {
+					t.Fatalf("expecting %s to contain, got %v", test.err, err)
+				}
+			} else if err != nil {
+				t.Fatalf("expecting nil, got %s", err)
+			}
+			for _, r := range test.reporters {
+				got := len(r.(*stubReporter).events)
+				if got != test.want {
+					t.Fatalf("expecting %d events, got %d", test.want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestError_WithFrames(t *testing.T) {
+	want := []Frame{{Function: "main.main", File: "main.go", Line: 10}}
+	e := NewInternal(nil, "message", "op").WithFrames(want)
+	if !reflect.DeepEqual(want, e.Frames()) {
+		t.Fatalf("expecting %v, got %v", want, e.Frames())
+	}
+	if len(e.ProgramCounters()) != 0 {
+		t.Fatalf("expecting program counters to be cleared, got %d", len(e.ProgramCounters()))
+	}
+}
@@ -0,0 +1,362 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// BatchReporter defines the method used for sending multiple
+// events at once. Reporters passed to NewAsyncReporter
+// that implement it receive whole batches.
+type BatchReporter interface {
+	ReportBatch(ctx context.Context, events []*Event) error
+}
+
+// WriterReporter writes events to an io.Writer as newline
+// delimited JSON.
+type WriterReporter struct {
+	mtx sync.Mutex
+	w   io.Writer
+}
+
+// NewWriterReporter returns a reporter that writes to w.
+func NewWriterReporter(w io.Writer) *WriterReporter {
+	return &WriterReporter{w: w}
+}
+
+// Report implements the Reporter interface by writing the
+// event as a single line of JSON.
+func (r *WriterReporter) Report(_ context.Context, event *Event) error {
+	buf, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	_, err = r.w.Write(append(buf, '\n'))
+	return err
+}
+
+// SlogReporter logs events to a structured logger, the log
+// level is derived from the event's level.
+type SlogReporter struct {
+	logger *slog.Logger
+}
+
+// NewSlogReporter returns a reporter that logs to l. If l is
+// nil, the default logger is used.
+func NewSlogReporter(l *slog.Logger) *SlogReporter {
+	if l == nil {
+		l = slog.Default()
+	}
+	return &SlogReporter{logger: l}
+}
+
+// Report implements the Reporter interface by logging the
+// event.
+func (r *SlogReporter) Report(ctx context.Context, event *Event) error {
+	attrs := []slog.Attr{
+		slog.String("event_id", event.ID),
+		slog.String("code", event.Code),
+	}
+	if event.ErrorID != "" {
+		attrs = append(attrs, slog.String("error_id", event.ErrorID))
+	}
+	if !event.Created.IsZero() {
+		attrs = append(attrs, slog.Time("created", event.Created))
+	}
+	if event.Elapsed != 0 {
+		attrs = append(attrs, slog.Duration("elapsed", event.Elapsed))
+	}
+	if env := event.Environment; env.Service != "" || env.Version != "" || env.Revision != "" {
+		attrs = append(attrs, slog.Group("environment",
+			slog.String("service", env.Service),
+			slog.String("version", env.Version),
+			slog.String("revision", env.Revision),
+		))
+	}
+	if event.Operation != "" {
+		attrs = append(attrs, slog.String("operation", event.Operation))
+	}
+	if len(event.Tags) > 0 {
+		tags := make([]any, 0, len(event.Tags))
+		for k, v := range event.Tags {
+			tags = append(tags, slog.String(k, v))
+		}
+		attrs = append(attrs, slog.Group("tags", tags...))
+	}
+	r.logger.LogAttrs(ctx, event.Severity.SlogLevel(), event.Message, attrs...)
+	return nil
+}
+
+var (
+	// ErrReporterClosed is returned when reporting to an
+	// AsyncReporter that has been closed.
+	ErrReporterClosed = errors.New("errors: reporter closed")
+	// ErrReporterQueueFull is returned when the queue of an
+	// AsyncReporter is full and the event was dropped.
+	ErrReporterQueueFull = errors.New("errors: reporter queue full")
+	// ErrReporterRateLimited is returned when the event was
+	// dropped by an AsyncReporter's rate limit.
+	ErrReporterRateLimited = errors.New("errors: reporter rate limited")
+)
+
+// AsyncOptions defines the options for an AsyncReporter.
+// Zero values are replaced by their defaults.
+type AsyncOptions struct {
+	// The maximum amount of events sent at once, defaults
+	// to 50.
+	BatchSize int
+	// The maximum amount of time an event is held before
+	// being sent, defaults to 5 seconds.
+	FlushInterval time.Duration
+	// The amount of events that can be queued before new
+	// events are dropped, defaults to 1000.
+	QueueSize int
+	// The maximum amount of time spent sending a batch,
+	// defaults to 30 seconds.
+	Timeout time.Duration
+	// The sustained amount of events accepted per second,
+	// zero disables rate limiting.
+	RateLimit float64
+	// The amount of events that can be accepted at once
+	// above the rate limit, defaults to BatchSize.
+	Burst int
+	// Called with any error returned from the underlying
+	// reporter, as sending happens in the background.
+	OnError func(err error)
+}
+
+// AsyncReporter queues events and sends them to the
+// underlying reporter in batches from a background
+// goroutine, dropping events when the queue is full or
+// the rate limit is exceeded.
+type AsyncReporter struct {
+	reporter Reporter
+	opts     AsyncOptions
+	queue    chan *Event
+	flush    chan chan struct{}
+	done     chan struct{}
+	mtx      sync.RWMutex
+	closed   bool
+	dropped  atomic.Uint64
+	limiter  *tokenBucket
+}
+
+// NewAsyncReporter returns an AsyncReporter sending to r and
+// starts the background goroutine. Close should be called
+// to send any remaining events.
+func NewAsyncReporter(r Reporter, opts AsyncOptions) *AsyncReporter {
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = 50
+	}
+	if opts.FlushInterval <= 0 {
+		opts.FlushInterval = 5 * time.Second
+	}
+	if opts.QueueSize <= 0 {
+		opts.QueueSize = 1000
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = 30 * time.Second
+	}
+	if opts.Burst <= 0 {
+		opts.Burst = opts.BatchSize
+	}
+	a := &AsyncReporter{
+		reporter: r,
+		opts:     opts,
+		queue:    make(chan *Event, opts.QueueSize),
+		flush:    make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if opts.RateLimit > 0 {
+		a.limiter = newTokenBucket(opts.RateLimit, opts.Burst)
+	}
+	go a.run()
+	return a
+}
+
+// Report implements the Reporter interface by queueing the
+// event, it never blocks.
+func (a *AsyncReporter) Report(_ context.Context, event *Event) error {
+	a.mtx.RLock()
+	defer a.mtx.RUnlock()
+	if a.closed {
+		return ErrReporterClosed
+	}
+	if a.limiter != nil && !a.limiter.allow() {
+		a.dropped.Add(1)
+		return ErrReporterRateLimited
+	}
+	select {
+	case a.queue <- event:
+		return nil
+	default:
+		a.dropped.Add(1)
+		return ErrReporterQueueFull
+	}
+}
+
+// Dropped returns the amount of events that have been
+// dropped due to the queue being full or rate limiting.
+func (a *AsyncReporter) Dropped() uint64 {
+	return a.dropped.Load()
+}
+
+// Flush sends all queued events, blocking until they have
+// been sent or the context is done.
+func (a *AsyncReporter) Flush(ctx context.Context) error {
+	ch := make(chan struct{})
+	select {
+	case a.flush <- ch:
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+	select {
+	case <-ch:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close stops accepting events and sends any that remain,
+// blocking until they have been sent or the context is done.
+func (a *AsyncReporter) Close(ctx context.Context) error {
+	a.mtx.Lock()
+	if !a.closed {
+		a.closed = true
+		close(a.queue)
+	}
+	a.mtx.Unlock()
+	select {
+	case <-a.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// run batches events from the queue until it's closed.
+func (a *AsyncReporter) run() {
+	defer close(a.done)
+
+	ticker := time.NewTicker(a.opts.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Event, 0, a.opts.BatchSize)
+	add := func(event *Event) {
+		batch = append(batch, event)
+		if len(batch) >= a.opts.BatchSize {
+			a.send(batch)
+			batch = make([]*Event, 0, a.opts.BatchSize)
+		}
+	}
+
+	for {
+		select {
+		case event, ok := <-a.queue:
+			if !ok {
+				a.send(batch)
+				return
+			}
+			add(event)
+		case <-ticker.C:
+			a.send(batch)
+			batch = make([]*Event, 0, a.opts.BatchSize)
+		case ch := <-a.flush:
+			a.drain(add)
+			a.send(batch)
+			batch = make([]*Event, 0, a.opts.BatchSize)
+			close(ch)
+		}
+	}
+}
+
+// drain adds every event currently in the queue without
+// blocking.
+func (a *AsyncReporter) drain(add func(*Event)) {
+	for {
+		select {
+		case event, ok := <-a.queue:
+			if !ok {
+				return
+			}
+			add(event)
+		default:
+			return
+		}
+	}
+}
+
+// send passes the batch to the underlying reporter.
+func (a *AsyncReporter) send(batch []*Event) {
+	if len(batch) == 0 {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), a.opts.Timeout)
+	defer cancel()
+	if br, ok := a.reporter.(BatchReporter); ok {
+		a.handle(br.ReportBatch(ctx, batch))
+		return
+	}
+	for _, event := range batch {
+		a.handle(a.reporter.Report(ctx, event))
+	}
+}
+
+// handle passes a non nil error to the OnError callback.
+func (a *AsyncReporter) handle(err error) {
+	if err != nil && a.opts.OnError != nil {
+		a.opts.OnError(err)
+	}
+}
+
+// tokenBucket is a minimal token bucket rate limiter.
+type tokenBucket struct {
+	mtx    sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	now    func() time.Time
+}
+
+// newTokenBucket returns a full bucket refilling at rate
+// tokens per second.
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	return &tokenBucket{
+		rate:   rate,
+		burst:  float64(burst),
+		tokens: float64(burst),
+		last:   time.Now(),
+		now:    time.Now,
+	}
+}
+
+// allow reports whether a token is available, consuming it
+// if so.
+func (b *tokenBucket) allow() bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	now := b.now()
+	b.tokens = min(b.burst, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
@@ -0,0 +1,241 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type batchStubReporter struct {
+	mtx     sync.Mutex
+	batches [][]*Event
+}
+
+func (b *batchStubReporter) Report(ctx context.Context, event *Event) error {
+	return b.ReportBatch(ctx, []*Event{event})
+}
+
+func (b *batchStubReporter) ReportBatch(_ context.Context, events []*Event) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	b.batches = append(b.batches, events)
+	return nil
+}
+
+func (b *batchStubReporter) sizes() []int {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	sizes := make([]int, len(b.batches))
+	for i, batch := range b.batches {
+		sizes[i] = len(batch)
+	}
+	return sizes
+}
+
+func TestWriterReporter_Report(t *testing.T) {
+	buf := &bytes.Buffer{}
+	r := NewWriterReporter(buf)
+	event := NewEvent(NewNotFound(nil, "message", "op"))
+
+	err := r.Report(context.Background(), event)
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+
+	var got Event
+	err = json.Unmarshal(buf.Bytes(), &got)
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	if !reflect.DeepEqual(event.ID, got.ID) {
+		t.Fatalf("expecting %s, got %s", event.ID, got.ID)
+	}
+	if !strings.HasSuffix(buf.String(), "}\n") {
+		t.Fatalf("expecting newline delimited output, got %s", buf.String())
+	}
+}
+
+func TestSlogReporter_Report(t *testing.T) {
+	tt := map[string]struct {
+		input error
+		want  string
+	}{
+		"Info": {
+			NewNotFound(nil, "message", "op"),
+			`"level":"INFO"`,
+		},
+		"Warning": {
+			NewConflict(nil, "message", "op"),
+			`"level":"WARN"`,
+		},
+		"Critical": {
+			NewInternal(nil, "message", "op").WithSeverity(SeverityCritical),
+			`"level":"ERROR+4"`,
+		},
+		"Error": {
+			NewInternal(nil, "message", "op").WithMeta("key", "value"),
+			`"tags":{"key":"value"}`,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			buf := &bytes.Buffer{}
+			r := NewSlogReporter(slog.New(slog.NewJSONHandler(buf, nil)))
+			err := r.Report(context.Background(), NewEvent(test.input))
+			if err != nil {
+				t.Fatalf("expecting nil, got %s", err)
+			}
+			if !strings.Contains(buf.String(), test.want) {
+				t.Fatalf("expecting %s to contain %s", buf.String(), test.want)
+			}
+		})
+	}
+}
+
+func TestAsyncReporter_Batching(t *testing.T) {
+	stub := &batchStubReporter{}
+	r := NewAsyncReporter(stub, AsyncOptions{BatchSize: 2, FlushInterval: time.Hour})
+
+	for i := 0; i < 5; i++ {
+		err := r.Report(context.Background(), NewEvent(NewInternal(nil, "message", "op")))
+		if err != nil {
+			t.Fatalf("expecting nil, got %s", err)
+		}
+	}
+
+	err := r.Close(context.Background())
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+
+	want := []int{2, 2, 1}
+	got := stub.sizes()
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expecting %v, got %v", want, got)
+	}
+}
+
+func TestAsyncReporter_Flush(t *testing.T) {
+	stub := &batchStubReporter{}
+	r := NewAsyncReporter(stub, AsyncOptions{FlushInterval: time.Hour})
+	defer r.Close(context.Background())
+
+	_ = r.Report(context.Background(), NewEvent(NewInternal(nil, "message", "op")))
+	err := r.Flush(context.Background())
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+
+	want := []int{1}
+	got := stub.sizes()
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expecting %v, got %v", want, got)
+	}
+}
+
+func TestAsyncReporter_RateLimit(t *testing.T) {
+	stub := &batchStubReporter{}
+	r := NewAsyncReporter(stub, AsyncOptions{RateLimit: 0.001, Burst: 2})
+	defer r.Close(context.Background())
+
+	var errs []error
+	for i := 0; i < 4; i++ {
+		errs = append(errs, r.Report(context.Background(), NewEvent(NewInternal(nil, "message", "op"))))
+	}
+
+	want := []error{nil, nil, ErrReporterRateLimited, ErrReporterRateLimited}
+	if !reflect.DeepEqual(want, errs) {
+		t.Fatalf("expecting %v, got %v", want, errs)
+	}
+	if r.Dropped() != 2 {
+		t.Fatalf("expecting 2 dropped, got %d", r.Dropped())
+	}
+}
+
+func TestAsyncReporter_Closed(t *testing.T) {
+	r := NewAsyncReporter(&stubReporter{}, AsyncOptions{})
+	_ = r.Close(context.Background())
+
+	got := r.Report(context.Background(), NewEvent(NewInternal(nil, "message", "op")))
+	if !reflect.DeepEqual(ErrReporterClosed, got) {
+		t.Fatalf("expecting %s, got %s", ErrReporterClosed, got)
+	}
+	if err := r.Flush(context.Background()); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+}
+
+func TestAsyncReporter_OnError(t *testing.T) {
+	var (
+		mtx  sync.Mutex
+		errs []error
+	)
+	r := NewAsyncReporter(&stubReporter{err: fmt.Errorf("error")}, AsyncOptions{
+		OnError: func(err error) {
+			mtx.Lock()
+			defer mtx.Unlock()
+			errs = append(errs, err)
+		},
+	})
+
+	_ = r.Report(context.Background(), NewEvent(NewInternal(nil, "message", "op")))
+	_ = r.Close(context.Background())
+
+	mtx.Lock()
+	defer mtx.Unlock()
+	if len(errs) != 1 {
+		t.Fatalf("expecting 1 error, got %d", len(errs))
+	}
+}
+
+type blockingReporter struct{}
+
+func (blockingReporter) Report(ctx context.Context, _ *Event) error {
+	<-ctx.Done()
+	return ctx.Err()
+}
+
+func TestAsyncReporter_Timeout(t *testing.T) {
+	errs := make(chan error, 1)
+	r := NewAsyncReporter(blockingReporter{}, AsyncOptions{
+		Timeout: time.Millisecond,
+		OnError: func(err error) { errs <- err },
+	})
+
+	_ = r.Report(context.Background(), NewEvent(NewInternal(nil, "message", "op")))
+	_ = r.Close(context.Background())
+
+	if err := <-errs; !Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expecting %s, got %v", context.DeadlineExceeded, err)
+	}
+}
+
+func TestTokenBucket_Allow(t *testing.T) {
+	now := time.Now()
+	b := newTokenBucket(1, 1)
+	b.now = func() time.Time { return now }
+	b.last = now
+
+	if !b.allow() {
+		t.Fatalf("expecting first token to be allowed")
+	}
+	if b.allow() {
+		t.Fatalf("expecting empty bucket")
+	}
+	now = now.Add(time.Second)
+	if !b.allow() {
+		t.Fatalf("expecting bucket to refill")
+	}
+}
@@ -0,0 +1,31 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+// Define returns a sentinel Error with the given code and
+// message, intended to be declared at package level.
+// Errors created from the sentinel with With satisfy
+// errors.Is(err, sentinel).
+//
+//	var ErrUserNotFound = errors.Define(errors.NOTFOUND, "User not found")
+func Define(code ErrorCode, message string) *Error {
+	return &Error{
+		Code:    code,
+		Message: message,
+	}
+}
+
+// With returns a new Error with the code and message of
+// the sentinel, wrapping err with the given operation and
+// capturing the stack at the point With is called.
+func (e *Error) With(err error, op string) *Error {
+	sentinel := e
+	if e.sentinel != nil {
+		sentinel = e.sentinel
+	}
+	ne := newError(err, e.Message, e.Code, op)
+	ne.sentinel = sentinel
+	return ne
+}
@@ -0,0 +1,102 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"database/sql"
+	"fmt"
+	"os"
+	"reflect"
+	"testing"
+)
+
+var (
+	errTestNotFound = Define(NOTFOUND, "User not found")
+	errTestConflict = Define(CONFLICT, "User already exists")
+)
+
+func TestDefine(t *testing.T) {
+	want := &Error{Code: NOTFOUND, Message: "User not found"}
+	got := Define(NOTFOUND, "User not found")
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expecting %+v, got %+v", want, got)
+	}
+}
+
+func TestError_With(t *testing.T) {
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("failed: %s", err.Error())
+	}
+
+	got := errTestNotFound.With(sql.ErrNoRows, "UserStore.Find")
+	want := &Error{
+		Code:      NOTFOUND,
+		Message:   "User not found",
+		Operation: "UserStore.Find",
+		Err:       sql.ErrNoRows,
+	}
+	UtilTestError(t, want, got)
+
+	if got.FileLine() != wd+"/sentinel_test.go:34" {
+		t.Fatalf("expecting %s, got %s", wd+"/sentinel_test.go:34", got.FileLine())
+	}
+	if got == errTestNotFound {
+		t.Fatalf("expecting a new error")
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	tt := map[string]struct {
+		err    error
+		target error
+		want   bool
+	}{
+		"Sentinel": {
+			errTestNotFound.With(nil, "op"),
+			errTestNotFound,
+			true,
+		},
+		"Wrapped": {
+			fmt.Errorf("wrap: %w", errTestNotFound.With(nil, "op")),
+			errTestNotFound,
+			true,
+		},
+		"Nested With": {
+			errTestNotFound.With(nil, "op").With(nil, "op"),
+			errTestNotFound,
+			true,
+		},
+		"Cause": {
+			errTestNotFound.With(sql.ErrNoRows, "op"),
+			sql.ErrNoRows,
+			true,
+		},
+		"Different Sentinel": {
+			errTestNotFound.With(nil, "op"),
+			errTestConflict,
+			false,
+		},
+		"Same Code": {
+			NewNotFound(nil, "User not found", "op"),
+			errTestNotFound,
+			false,
+		},
+		"Non Error Target": {
+			errTestNotFound.With(nil, "op"),
+			sql.ErrNoRows,
+			false,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := Is(test.err, test.target)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %t, got %t", test.want, got)
+			}
+		})
+	}
+}
@@ -0,0 +1,241 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// sentryClient is the client name sent in the auth header.
+const sentryClient = "ainsleyclark-errors/1.0"
+
+// sentryTimeout is the timeout of the default client.
+const sentryTimeout = 10 * time.Second
+
+// SentryOptions defines the options for a SentryReporter.
+type SentryOptions struct {
+	// The Data Source Name of the Sentry project, in the form
+	// of https://<key>@<host>/<project>.
+	DSN string
+	// The client used for sending envelopes, defaults to a
+	// client with a 10 second timeout.
+	Client *http.Client
+	// Optional deployment details attached to each event.
+	Environment string
+	Release     string
+	ServerName  string
+}
+
+// SentryReporter sends events to Sentry's envelope endpoint
+// without requiring the Sentry SDK.
+type SentryReporter struct {
+	opts     SentryOptions
+	endpoint string
+	key      string
+}
+
+// NewSentryReporter parses the DSN and returns a reporter
+// sending to its envelope endpoint.
+func NewSentryReporter(opts SentryOptions) (*SentryReporter, error) {
+	u, err := url.Parse(opts.DSN)
+	if err != nil {
+		return nil, fmt.Errorf("errors: invalid sentry dsn: %w", err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("errors: sentry dsn missing public key")
+	}
+	idx := strings.LastIndex(u.Path, "/")
+	project := u.Path[idx+1:]
+	if idx < 0 || project == "" {
+		return nil, fmt.Errorf("errors: sentry dsn missing project id")
+	}
+	if opts.Client == nil {
+		opts.Client = &http.Client{Timeout: sentryTimeout}
+	}
+	return &SentryReporter{
+		opts:     opts,
+		endpoint: u.Scheme + "://" + u.Host + u.Path[:idx] + "/api/" + project + "/envelope/",
+		key:      u.User.Username(),
+	}, nil
+}
+
+// Report implements the Reporter interface by posting the
+// event as an envelope.
+func (s *SentryReporter) Report(ctx context.Context, event *Event) error {
+	envelope, err := s.Encode(event)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.endpoint, bytes.NewReader(envelope))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/x-sentry-envelope")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=%s, sentry_key=%s", sentryClient, s.key))
+
+	resp, err := s.opts.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("errors: sentry responded with status %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// Encode returns the event as a Sentry envelope containing
+// a single event item.
+func (s *SentryReporter) Encode(event *Event) ([]byte, error) {
+	payload, err := json.Marshal(s.sentryEvent(event))
+	if err != nil {
+		return nil, err
+	}
+
+	header, err := json.Marshal(map[string]string{
+		"event_id": event.ID,
+		"sent_at":  time.Now().UTC().Format(time.RFC3339),
+		"dsn":      s.opts.DSN,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	item, err := json.Marshal(map[string]any{
+		"type":         "event",
+		"length":       len(payload),
+		"content_type": "application/json",
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Write(header)
+	buf.WriteByte('\n')
+	buf.Write(item)
+	buf.WriteByte('\n')
+	buf.Write(payload)
+	buf.WriteByte('\n')
+
+	return buf.Bytes(), nil
+}
+
+type (
+	// sentryEvent is the event payload of an envelope.
+	sentryEvent struct {
+		EventID     string            `json:"event_id"`
+		Timestamp   string            `json:"timestamp"`
+		Level       string            `json:"level"`
+		Platform    string            `json:"platform"`
+		Message     string            `json:"message,omitempty"`
+		Transaction string            `json:"transaction,omitempty"`
+		Environment string            `json:"environment,omitempty"`
+		Release     string            `json:"release,omitempty"`
+		ServerName  string            `json:"server_name,omitempty"`
+		Fingerprint []string          `json:"fingerprint,omitempty"`
+		Tags        map[string]string `json:"tags,omitempty"`
+		Exception   sentryExceptions  `json:"exception"`
+	}
+	// sentryExceptions is the exception interface of an event.
+	sentryExceptions struct {
+		Values []sentryException `json:"values"`
+	}
+	// sentryException is a singular exception within the
+	// exception interface.
+	sentryException struct {
+		Type       string            `json:"type"`
+		Value      string            `json:"value"`
+		Stacktrace *sentryStacktrace `json:"stacktrace,omitempty"`
+	}
+	// sentryStacktrace defines the frames of an exception,
+	// with the oldest call first.
+	sentryStacktrace struct {
+		Frames []sentryFrame `json:"frames"`
+	}
+	// sentryFrame is a singular frame of a stacktrace.
+	sentryFrame struct {
+		Function string `json:"function"`
+		AbsPath  string `json:"abs_path"`
+		Lineno   int    `json:"lineno"`
+	}
+)
+
+// sentryEvent transforms the event into Sentry's event
+// payload.
+func (s *SentryReporter) sentryEvent(event *Event) sentryEvent {
+	se := sentryEvent{
+		EventID:     event.ID,
+		Timestamp:   event.Timestamp.UTC().Format(time.RFC3339Nano),
+		Level:       event.Level,
+		Platform:    "go",
+		Message:     event.Message,
+		Transaction: event.Operation,
+		Environment: s.opts.Environment,
+		Release:     s.opts.Release,
+		ServerName:  s.opts.ServerName,
+		Fingerprint: event.Fingerprint,
+		Exception: sentryExceptions{
+			Values: make([]sentryException, 0, len(event.Exceptions)),
+		},
+	}
+
+	if se.Release == "" {
+		se.Release = event.Environment.Version
+	}
+	if se.ServerName == "" {
+		se.ServerName = event.Environment.Hostname
+	}
+	extra := map[string]string{
+		"error_id": event.ErrorID,
+		"service":  event.Environment.Service,
+		"revision": event.Environment.Revision,
+	}
+	se.Tags = make(map[string]string, len(event.Tags)+len(extra))
+	for k, v := range extra {
+		if v != "" {
+			se.Tags[k] = v
+		}
+	}
+	for k, v := range event.Tags {
+		se.Tags[k] = v
+	}
+
+	for _, ex := range event.Exceptions {
+		value := ex.Value
+		if ex.Operation != "" {
+			value = ex.Operation + ": " + value
+		}
+		exception := sentryException{
+			Type:  ex.Type,
+			Value: value,
+		}
+		if len(ex.Frames) > 0 {
+			frames := make([]sentryFrame, len(ex.Frames))
+			for i, f := range ex.Frames {
+				frames[len(ex.Frames)-1-i] = sentryFrame{
+					Function: f.Function,
+					AbsPath:  f.File,
+					Lineno:   f.Line,
+				}
+			}
+			exception.Stacktrace = &sentryStacktrace{Frames: frames}
+		}
+		se.Exception.Values = append(se.Exception.Values, exception)
+	}
+
+	return se
+}
@@ -0,0 +1,184 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestNewSentryReporter(t *testing.T) {
+	tt := map[string]struct {
+		input string
+		want  string
+	}{
+		"Success": {
+			"https://key@sentry.example.com/42",
+			"https://sentry.example.com/api/42/envelope/",
+		},
+		"Path Prefix": {
+			"https://key@example.com/sentry/42",
+			"https://example.com/sentry/api/42/envelope/",
+		},
+		"Parse Error": {
+			"://key@",
+			"invalid sentry dsn",
+		},
+		"No Key": {
+			"https://sentry.example.com/42",
+			"missing public key",
+		},
+		"No Project": {
+			"https://key@sentry.example.com",
+			"missing project id",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got, err := NewSentryReporter(SentryOptions{DSN: test.input})
+			if err != nil {
+				if !strings.Contains(err.Error(), test.want) {
+					t.Fatalf("expecting %s to contain, got %s", test.want, err)
+				}
+				return
+			}
+			if !reflect.DeepEqual(test.want, got.endpoint) {
+				t.Fatalf("expecting %s, got %s", test.want, got.endpoint)
+			}
+		})
+	}
+}
+
+func TestSentryReporter_Encode(t *testing.T) {
+	r, err := NewSentryReporter(SentryOptions{DSN: "https://key@sentry.example.com/42", Release: "v1.0.0"})
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+
+	event := NewEvent(NewNotFound(fmt.Errorf("error"), "message", "op"))
+	got, err := r.Encode(event)
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+
+	lines := bytes.Split(bytes.TrimSuffix(got, []byte("\n")), []byte("\n"))
+	if len(lines) != 3 {
+		t.Fatalf("expecting 3 lines, got %d", len(lines))
+	}
+
+	var header map[string]string
+	if err = json.Unmarshal(lines[0], &header); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	if !reflect.DeepEqual(event.ID, header["event_id"]) {
+		t.Fatalf("expecting %s, got %s", event.ID, header["event_id"])
+	}
+
+	var item struct {
+		Type   string `json:"type"`
+		Length int    `json:"length"`
+	}
+	if err = json.Unmarshal(lines[1], &item); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	if item.Type != "event" || item.Length != len(lines[2]) {
+		t.Fatalf("expecting event item of length %d, got %+v", len(lines[2]), item)
+	}
+
+	var payload sentryEvent
+	if err = json.Unmarshal(lines[2], &payload); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	if payload.Level != LevelInfo || payload.Release != "v1.0.0" || payload.Transaction != "op" {
+		t.Fatalf("unexpected payload %+v", payload)
+	}
+	values := payload.Exception.Values
+	if len(values) != 2 || values[1].Type != NOTFOUND.String() || values[1].Value != "op: message" {
+		t.Fatalf("unexpected exceptions %+v", values)
+	}
+	frames := values[1].Stacktrace.Frames
+	want := "github.com/ainsleyclark/errors.TestSentryReporter_Encode"
+	if !reflect.DeepEqual(want, frames[len(frames)-1].Function) {
+		t.Fatalf("expecting %s, got %s", want, frames[len(frames)-1].Function)
+	}
+}
+
+func TestSentryReporter_Report(t *testing.T) {
+	tt := map[string]struct {
+		status int
+		want   any
+	}{
+		"Success": {
+			http.StatusOK,
+			nil,
+		},
+		"Bad Status": {
+			http.StatusTooManyRequests,
+			"sentry responded with status 429",
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			var (
+				auth string
+				body []byte
+				path string
+			)
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				auth = r.Header.Get("X-Sentry-Auth")
+				path = r.URL.Path
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(test.status)
+			}))
+			defer server.Close()
+
+			dsn := strings.Replace(server.URL, "://", "://public@", 1) + "/7"
+			r, err := NewSentryReporter(SentryOptions{DSN: dsn, Client: server.Client()})
+			if err != nil {
+				t.Fatalf("expecting nil, got %s", err)
+			}
+
+			got := r.Report(context.Background(), NewEvent(NewInternal(nil, "message", "op")))
+			if got != nil {
+				if !strings.Contains(got.Error(), fmt.Sprintf("%s", test.want)) {
+					t.Fatalf("expecting %s to contain, got %s", test.want, got)
+				}
+				return
+			}
+			if test.want != nil {
+				t.Fatalf("expecting %s, got nil", test.want)
+			}
+			if path != "/api/7/envelope/" {
+				t.Fatalf("expecting envelope path, got %s", path)
+			}
+			if !strings.Contains(auth, "sentry_key=public") {
+				t.Fatalf("expecting auth header to contain key, got %s", auth)
+			}
+			if !bytes.Contains(body, []byte(`"type":"event"`)) {
+				t.Fatalf("expecting event item, got %s", body)
+			}
+		})
+	}
+}
+
+func TestNewSentryReporter_Client(t *testing.T) {
+	got, err := NewSentryReporter(SentryOptions{DSN: "https://key@sentry.example.com/42"})
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	if got.opts.Client.Timeout != sentryTimeout {
+		t.Fatalf("expecting %s, got %s", sentryTimeout, got.opts.Client.Timeout)
+	}
+}
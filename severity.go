@@ -0,0 +1,147 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"log/slog"
+	"sync"
+)
+
+// Severity defines how serious an error is, so loggers and
+// reporters can decide how loudly to surface it.
+type Severity int
+
+// Severity levels, the zero value indicates that no
+// severity has been set.
+const (
+	// SeverityDebug - Diagnostic information.
+	SeverityDebug Severity = iota + 1
+	// SeverityInfo - Expected client errors, such as an
+	// entity not being found.
+	SeverityInfo
+	// SeverityWarn - Errors worth keeping an eye on.
+	SeverityWarn
+	// SeverityError - Failures within the application.
+	SeverityError
+	// SeverityCritical - Failures that require immediate
+	// attention.
+	SeverityCritical
+)
+
+// SlogLevelCritical is the slog level used for
+// SeverityCritical, as slog has no level above error.
+const SlogLevelCritical = slog.LevelError + 4
+
+var (
+	severitiesMtx sync.RWMutex
+	// severities maps the error codes to their severity.
+	severities = map[ErrorCode]Severity{
+		CONFLICT:            SeverityWarn,
+		INTERNAL:            SeverityError,
+		INVALID:             SeverityInfo,
+		NOTFOUND:            SeverityInfo,
+		UNKNOWN:             SeverityError,
+		MAXIMUMATTEMPTS:     SeverityWarn,
+		EXPIRED:             SeverityInfo,
+		CANCELLED:           SeverityInfo,
+		DEADLINE_EXCEEDED:   SeverityWarn,
+		UNAUTHENTICATED:     SeverityInfo,
+		PERMISSION_DENIED:   SeverityWarn,
+		UNAVAILABLE:         SeverityError,
+		UNIMPLEMENTED:       SeverityError,
+		FAILED_PRECONDITION: SeverityInfo,
+		ALREADY_EXISTS:      SeverityInfo,
+		ABORTED:             SeverityWarn,
+		OUT_OF_RANGE:        SeverityInfo,
+		DATA_LOSS:           SeverityCritical,
+		RESOURCE_EXHAUSTED:  SeverityWarn,
+	}
+)
+
+// String returns the lowercase name of the severity.
+func (s Severity) String() string {
+	switch s {
+	case SeverityDebug:
+		return "debug"
+	case SeverityInfo:
+		return "info"
+	case SeverityWarn:
+		return "warn"
+	case SeverityError:
+		return "error"
+	case SeverityCritical:
+		return "critical"
+	}
+	return ""
+}
+
+// SlogLevel converts the severity to a slog.Level. Unset
+// severities are treated as errors.
+func (s Severity) SlogLevel() slog.Level {
+	switch s {
+	case SeverityDebug:
+		return slog.LevelDebug
+	case SeverityInfo:
+		return slog.LevelInfo
+	case SeverityWarn:
+		return slog.LevelWarn
+	case SeverityCritical:
+		return SlogLevelCritical
+	}
+	return slog.LevelError
+}
+
+// SetSeverity configures the severity for the given error
+// code, overriding the default.
+func SetSeverity(code ErrorCode, s Severity) {
+	severitiesMtx.Lock()
+	defer severitiesMtx.Unlock()
+	severities[code] = s
+}
+
+// CodeSeverity returns the severity configured for the
+// given error code, or its nearest parent for hierarchical
+// codes. Codes without a severity are treated as errors.
+func CodeSeverity(code ErrorCode) Severity {
+	severitiesMtx.RLock()
+	defer severitiesMtx.RUnlock()
+	for ; code != ""; code = code.Parent() {
+		if s, ok := severities[code]; ok {
+			return s
+		}
+	}
+	return SeverityError
+}
+
+// WithSeverity overrides the severity of the error,
+// regardless of its code, and returns the error to allow
+// for chaining.
+func (e *Error) WithSeverity(s Severity) *Error {
+	e.severity = s
+	return e
+}
+
+// SeverityOf returns the severity of the error. The
+// outermost override set with WithSeverity takes
+// precedence, otherwise the severity configured for the
+// error's code is returned.
+// If err is nil, SeverityOf returns zero.
+func SeverityOf(err error) Severity {
+	if err == nil {
+		return 0
+	}
+	for e := err; e != nil; e = Unwrap(e) {
+		if x, ok := e.(*Error); ok && x.severity != 0 {
+			return x.severity
+		}
+	}
+	return CodeSeverity(Code(err))
+}
+
+// SlogLevel returns the slog.Level for the severity of the
+// error, so loggers can pick a level automatically.
+func SlogLevel(err error) slog.Level {
+	return SeverityOf(err).SlogLevel()
+}
@@ -0,0 +1,135 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"fmt"
+	"log/slog"
+	"reflect"
+	"testing"
+)
+
+func TestSeverity_String(t *testing.T) {
+	tt := map[string]struct {
+		input Severity
+		want  string
+	}{
+		"Debug":    {SeverityDebug, "debug"},
+		"Info":     {SeverityInfo, "info"},
+		"Warn":     {SeverityWarn, "warn"},
+		"Error":    {SeverityError, "error"},
+		"Critical": {SeverityCritical, "critical"},
+		"Unset":    {0, ""},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := test.input.String()
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestSeverity_SlogLevel(t *testing.T) {
+	tt := map[string]struct {
+		input Severity
+		want  slog.Level
+	}{
+		"Debug":    {SeverityDebug, slog.LevelDebug},
+		"Info":     {SeverityInfo, slog.LevelInfo},
+		"Warn":     {SeverityWarn, slog.LevelWarn},
+		"Error":    {SeverityError, slog.LevelError},
+		"Critical": {SeverityCritical, SlogLevelCritical},
+		"Unset":    {0, slog.LevelError},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := test.input.SlogLevel()
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestSetSeverity(t *testing.T) {
+	const code = "test_severity"
+	t.Cleanup(func() {
+		severitiesMtx.Lock()
+		delete(severities, code)
+		severitiesMtx.Unlock()
+	})
+
+	if got := CodeSeverity(code); got != SeverityError {
+		t.Fatalf("expecting %s, got %s", SeverityError, got)
+	}
+	SetSeverity(code, SeverityDebug)
+	if got := CodeSeverity(code); got != SeverityDebug {
+		t.Fatalf("expecting %s, got %s", SeverityDebug, got)
+	}
+}
+
+func TestSeverityOf(t *testing.T) {
+	tt := map[string]struct {
+		input error
+		want  Severity
+	}{
+		"Nil": {
+			nil,
+			0,
+		},
+		"Not Found": {
+			NewNotFound(nil, "message", "op"),
+			SeverityInfo,
+		},
+		"Internal": {
+			NewInternal(nil, "message", "op"),
+			SeverityError,
+		},
+		"Standard Error": {
+			fmt.Errorf("error"),
+			SeverityError,
+		},
+		"Inherited Code": {
+			&Error{Err: NewInvalid(nil, "message", "op")},
+			SeverityInfo,
+		},
+		"Override": {
+			NewInternal(nil, "message", "op").WithSeverity(SeverityCritical),
+			SeverityCritical,
+		},
+		"Wrapped Override": {
+			fmt.Errorf("wrap: %w", NewNotFound(nil, "message", "op").WithSeverity(SeverityWarn)),
+			SeverityWarn,
+		},
+		"Sub Code": {
+			&Error{Code: "not_found.user"},
+			SeverityInfo,
+		},
+		"Outer Override": {
+			NewInternal(NewInternal(nil, "", "").WithSeverity(SeverityDebug), "", "").WithSeverity(SeverityCritical),
+			SeverityCritical,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := SeverityOf(test.input)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestSlogLevel(t *testing.T) {
+	got := SlogLevel(NewNotFound(nil, "message", "op"))
+	if !reflect.DeepEqual(slog.LevelInfo, got) {
+		t.Fatalf("expecting %s, got %s", slog.LevelInfo, got)
+	}
+}
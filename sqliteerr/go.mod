@@ -0,0 +1,10 @@
+module github.com/ainsleyclark/errors/sqliteerr
+
+go 1.21
+
+require (
+	github.com/ainsleyclark/errors v0.0.0
+	github.com/mattn/go-sqlite3 v1.14.52
+)
+
+replace github.com/ainsleyclark/errors => ../
@@ -0,0 +1,110 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+// Package sqliteerr converts SQLite errors from
+// mattn/go-sqlite3 into application errors, using the
+// extended result code to determine the error code.
+//
+// As with the driver, the package requires cgo.
+package sqliteerr
+
+import (
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/ainsleyclark/errors"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Metadata keys set on errors returned by FromError.
+const (
+	// CodeKey is the extended result code returned by SQLite.
+	CodeKey = "sqlite_code"
+	// ConstraintKey is the name of the violated constraint.
+	ConstraintKey = "constraint"
+	// TableKey is the name of the table.
+	TableKey = "table"
+	// ColumnKey is the name of the column.
+	ColumnKey = "column"
+)
+
+// FromError returns an Error with the code determined by
+// Classify, falling back to INTERNAL. The extended result
+// code and the constraint, table and column parsed from
+// the error message are stored in the metadata.
+// If err is nil, FromError returns nil.
+func FromError(err error, message, op string) *errors.Error {
+	if err == nil {
+		return nil
+	}
+
+	code := Classify(err)
+	if code == "" {
+		code = errors.INTERNAL
+	}
+
+	e := errors.NewDepth(1, err, message, code, op)
+
+	var liteErr sqlite3.Error
+	if !errors.As(err, &liteErr) {
+		return e
+	}
+
+	e.WithMeta(CodeKey, strconv.Itoa(int(liteErr.ExtendedCode)))
+	for k, v := range parse(liteErr.Error()) {
+		if v != "" {
+			e.WithMeta(k, v)
+		}
+	}
+
+	return e
+}
+
+// Classify returns the error code for SQLite errors, or an
+// empty string if the error is not recognised. It can be
+// passed to errors.RegisterClassifier.
+//
+//   - No rows returns NOTFOUND.
+//   - Unique and primary key violations return CONFLICT.
+//   - Check, not null and foreign key violations return INVALID.
+func Classify(err error) errors.ErrorCode {
+	if errors.Is(err, sql.ErrNoRows) {
+		return errors.NOTFOUND
+	}
+	var liteErr sqlite3.Error
+	if !errors.As(err, &liteErr) {
+		return ""
+	}
+	switch liteErr.ExtendedCode {
+	case sqlite3.ErrConstraintUnique, sqlite3.ErrConstraintPrimaryKey:
+		return errors.CONFLICT
+	case sqlite3.ErrConstraintCheck, sqlite3.ErrConstraintNotNull, sqlite3.ErrConstraintForeignKey:
+		return errors.INVALID
+	}
+	return ""
+}
+
+// parse obtains the constraint, table and column from the
+// error message, such as "UNIQUE constraint failed:
+// users.email", as SQLite doesn't return them separately.
+func parse(msg string) map[string]string {
+	meta := make(map[string]string)
+	kind, detail, ok := strings.Cut(msg, " constraint failed: ")
+	if !ok {
+		return meta
+	}
+	switch kind {
+	case "UNIQUE", "NOT NULL", "PRIMARY KEY":
+		// Multi column constraints are separated by a comma,
+		// the first column is used.
+		first, _, _ := strings.Cut(detail, ", ")
+		if table, column, ok := strings.Cut(first, "."); ok {
+			meta[TableKey], meta[ColumnKey] = table, column
+		}
+	case "CHECK":
+		meta[ConstraintKey] = detail
+	}
+	return meta
+}
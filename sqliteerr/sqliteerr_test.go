@@ -0,0 +1,128 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package sqliteerr
+
+import (
+	"database/sql"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/ainsleyclark/errors"
+	"github.com/mattn/go-sqlite3"
+)
+
+func constraintErr(code sqlite3.ErrNoExtended) sqlite3.Error {
+	return sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: code}
+}
+
+func TestClassify(t *testing.T) {
+	tt := map[string]struct {
+		input error
+		want  errors.ErrorCode
+	}{
+		"No Rows":     {sql.ErrNoRows, errors.NOTFOUND},
+		"Unique":      {constraintErr(sqlite3.ErrConstraintUnique), errors.CONFLICT},
+		"Primary Key": {constraintErr(sqlite3.ErrConstraintPrimaryKey), errors.CONFLICT},
+		"Check":       {constraintErr(sqlite3.ErrConstraintCheck), errors.INVALID},
+		"Not Null":    {constraintErr(sqlite3.ErrConstraintNotNull), errors.INVALID},
+		"Foreign Key": {constraintErr(sqlite3.ErrConstraintForeignKey), errors.INVALID},
+		"Wrapped":     {fmt.Errorf("wrap: %w", constraintErr(sqlite3.ErrConstraintUnique)), errors.CONFLICT},
+		"Busy":        {sqlite3.Error{Code: sqlite3.ErrBusy}, ""},
+		"Unknown":     {fmt.Errorf("error"), ""},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := Classify(test.input)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestFromError(t *testing.T) {
+	tt := map[string]struct {
+		input error
+		code  errors.ErrorCode
+		meta  map[string]string
+	}{
+		"Unique": {
+			constraintErr(sqlite3.ErrConstraintUnique),
+			errors.CONFLICT,
+			map[string]string{CodeKey: "2067"},
+		},
+		"Busy": {
+			sqlite3.Error{Code: sqlite3.ErrBusy, ExtendedCode: sqlite3.ErrBusyRecovery},
+			errors.INTERNAL,
+			map[string]string{CodeKey: "261"},
+		},
+		"No Rows": {
+			sql.ErrNoRows,
+			errors.NOTFOUND,
+			map[string]string{},
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := FromError(test.input, "message", "op")
+			if !reflect.DeepEqual(test.code, got.Code) {
+				t.Fatalf("expecting %s, got %s", test.code, got.Code)
+			}
+			if !reflect.DeepEqual(test.meta, errors.Metadata(got)) {
+				t.Fatalf("expecting %v, got %v", test.meta, errors.Metadata(got))
+			}
+			if !strings.Contains(got.FileLine(), "sqliteerr_test.go") {
+				t.Fatalf("expecting file line in test, got %s", got.FileLine())
+			}
+		})
+	}
+}
+
+func TestFromError_Nil(t *testing.T) {
+	if got := FromError(nil, "message", "op"); got != nil {
+		t.Fatalf("expecting nil, got %s", got)
+	}
+}
+
+func TestParse(t *testing.T) {
+	tt := map[string]struct {
+		input string
+		want  map[string]string
+	}{
+		"Unique": {
+			"UNIQUE constraint failed: users.email",
+			map[string]string{TableKey: "users", ColumnKey: "email"},
+		},
+		"Unique Multi Column": {
+			"UNIQUE constraint failed: users.first, users.last",
+			map[string]string{TableKey: "users", ColumnKey: "first"},
+		},
+		"Not Null": {
+			"NOT NULL constraint failed: users.name",
+			map[string]string{TableKey: "users", ColumnKey: "name"},
+		},
+		"Check": {
+			"CHECK constraint failed: age_check",
+			map[string]string{ConstraintKey: "age_check"},
+		},
+		"Foreign Key": {
+			"FOREIGN KEY constraint failed",
+			map[string]string{},
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := parse(test.input)
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %v, got %v", test.want, got)
+			}
+		})
+	}
+}
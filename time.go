@@ -0,0 +1,67 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"context"
+	"time"
+)
+
+// startKey is the context key for the start time.
+type startKey struct{}
+
+// ContextWithStart returns a copy of ctx holding the time
+// in which the unit of work began, such as when a request
+// was received or a message was queued. See WithElapsed.
+func ContextWithStart(ctx context.Context, start time.Time) context.Context {
+	return context.WithValue(ctx, startKey{}, start)
+}
+
+// StartFromContext returns the start time stored in ctx by
+// ContextWithStart, if any.
+func StartFromContext(ctx context.Context) (time.Time, bool) {
+	start, ok := ctx.Value(startKey{}).(time.Time)
+	return start, ok
+}
+
+// Time returns the time in which the error was created.
+func (e *Error) Time() time.Time {
+	return e.time
+}
+
+// WithTime sets the time in which the error was created and
+// returns the error to allow for chaining. It's intended for
+// restoring errors decoded from other formats.
+func (e *Error) WithTime(t time.Time) *Error {
+	e.time = t
+	return e
+}
+
+// Age returns the amount of time that has passed since the
+// error was created, useful for seeing how stale an error
+// is by the time it's handled. Errors without a creation
+// time return zero.
+func (e *Error) Age() time.Duration {
+	if e.time.IsZero() {
+		return 0
+	}
+	return now().Sub(e.time)
+}
+
+// WithElapsed records the duration between the start time
+// stored in ctx and the creation of the error, and returns
+// the error to allow for chaining. If ctx has no start time,
+// the error is unchanged.
+func (e *Error) WithElapsed(ctx context.Context) *Error {
+	if start, ok := StartFromContext(ctx); ok && !e.time.IsZero() {
+		e.elapsed = e.time.Sub(start)
+	}
+	return e
+}
+
+// Elapsed returns the duration recorded by WithElapsed.
+func (e *Error) Elapsed() time.Duration {
+	return e.elapsed
+}
@@ -0,0 +1,133 @@
+// Copyright 2022 Ainsley Clark. All rights reserved.
+// Use of this source code is governed by a MIT-style
+// license that can be found in the LICENSE file.
+
+package errors
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestError_Time(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	withConfig(t, func(cfg *Config) { cfg.Clock = func() time.Time { return now } })
+
+	e := NewInternal(nil, "message", "op")
+	if !reflect.DeepEqual(now, e.Time()) {
+		t.Fatalf("expecting %s, got %s", now, e.Time())
+	}
+	if got := (&Error{}).Time(); !got.IsZero() {
+		t.Fatalf("expecting zero time, got %s", got)
+	}
+}
+
+func TestError_WithTime(t *testing.T) {
+	want := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	got := NewInternal(nil, "message", "op").WithTime(want).Time()
+	if !reflect.DeepEqual(want, got) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
+
+func TestError_Age(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	withConfig(t, func(cfg *Config) { cfg.Clock = func() time.Time { return now } })
+	e := NewInternal(nil, "message", "op")
+
+	withConfig(t, func(cfg *Config) { cfg.Clock = func() time.Time { return now.Add(time.Minute) } })
+	if got := e.Age(); got != time.Minute {
+		t.Fatalf("expecting %s, got %s", time.Minute, got)
+	}
+	if got := (&Error{}).Age(); got != 0 {
+		t.Fatalf("expecting zero, got %s", got)
+	}
+}
+
+func TestError_WithElapsed(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	withConfig(t, func(cfg *Config) { cfg.Clock = func() time.Time { return now } })
+
+	tt := map[string]struct {
+		input context.Context
+		want  time.Duration
+	}{
+		"With Start": {
+			ContextWithStart(context.Background(), now.Add(-3*time.Second)),
+			3 * time.Second,
+		},
+		"No Start": {
+			context.Background(),
+			0,
+		},
+	}
+
+	for name, test := range tt {
+		t.Run(name, func(t *testing.T) {
+			got := NewInternal(nil, "message", "op").WithElapsed(test.input).Elapsed()
+			if !reflect.DeepEqual(test.want, got) {
+				t.Fatalf("expecting %s, got %s", test.want, got)
+			}
+		})
+	}
+}
+
+func TestStartFromContext(t *testing.T) {
+	start := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	got, ok := StartFromContext(ContextWithStart(context.Background(), start))
+	if !ok || !got.Equal(start) {
+		t.Fatalf("expecting %s, got %s", start, got)
+	}
+	if _, ok = StartFromContext(context.Background()); ok {
+		t.Fatal("expecting no start time")
+	}
+}
+
+func TestError_JSONTime(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 5, time.UTC)
+	withConfig(t, func(cfg *Config) { cfg.Clock = func() time.Time { return now } })
+	ctx := ContextWithStart(context.Background(), now.Add(-1500*time.Millisecond))
+	e := NewInternal(nil, "message", "op").WithElapsed(ctx)
+
+	buf, err := json.Marshal(e)
+	if err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	want := `"time":"2022-01-01T12:00:00.000000005Z","elapsed":"1.5s"`
+	if !strings.Contains(string(buf), want) {
+		t.Fatalf("expecting %s to contain %s", buf, want)
+	}
+
+	got := &Error{}
+	if err = json.Unmarshal(buf, got); err != nil {
+		t.Fatalf("expecting nil, got %s", err)
+	}
+	if !got.Time().Equal(now) || got.Elapsed() != 1500*time.Millisecond {
+		t.Fatalf("expecting time and elapsed to survive, got %s and %s", got.Time(), got.Elapsed())
+	}
+
+	for _, input := range []string{`{"time":"yesterday"}`, `{"elapsed":"long"}`} {
+		if err = json.Unmarshal([]byte(input), &Error{}); err == nil {
+			t.Fatalf("expecting error for %s", input)
+		}
+	}
+}
+
+func TestNewEvent_Time(t *testing.T) {
+	now := time.Date(2022, 1, 1, 12, 0, 0, 0, time.UTC)
+	withConfig(t, func(cfg *Config) { cfg.Clock = func() time.Time { return now } })
+	ctx := ContextWithStart(context.Background(), now.Add(-time.Second))
+	inner := NewNotFound(nil, "message", "op").WithElapsed(ctx)
+
+	event := NewEvent(NewInternal(inner, "message", "op"))
+	if !event.Created.Equal(now) {
+		t.Fatalf("expecting %s, got %s", now, event.Created)
+	}
+	if event.Elapsed != time.Second {
+		t.Fatalf("expecting %s, got %s", time.Second, event.Elapsed)
+	}
+}
@@ -11,18 +11,40 @@ import (
 
 // newError is an alias for New by creating the pcs
 // file line and constructing the error message.
-func newError(err error, message, code, op string) *Error {
-	_, file, line, _ := runtime.Caller(2)
-	pcs := make([]uintptr, 100)
-	_ = runtime.Callers(3, pcs)
-	return &Error{
+func newError(err error, message string, code ErrorCode, op string) *Error {
+	return newErrorSkip(1, err, message, code, op)
+}
+
+// newErrorSkip creates the error, skipping the given
+// amount of additional stack frames when obtaining the
+// pcs and file line.
+func newErrorSkip(skip int, err error, message string, code ErrorCode, op string) *Error {
+	cfg := loadConfig()
+	_, file, line, _ := runtime.Caller(skip + 2)
+	pcs := make([]uintptr, cfg.StackDepth)
+	_ = runtime.Callers(skip+3, pcs)
+	e := &Error{
 		Code:      code,
 		Message:   message,
 		Operation: op,
 		Err:       err,
 		fileLine:  file + ":" + strconv.Itoa(line),
 		pcs:       pcs,
+		time:      now(),
 	}
+	if e.id = ID(err); e.id == "" && cfg.GenerateIDs {
+		e.id = newID(e.time)
+	}
+	return e
+}
+
+// NewDepth returns an Error with the given code, skipping
+// the given amount of stack frames above the caller when
+// capturing the file line and stack. It's intended for
+// packages that construct errors on behalf of their
+// callers, a skip of zero behaves the same as NewE.
+func NewDepth(skip int, err error, message string, code ErrorCode, op string) *Error {
+	return newErrorSkip(skip, err, message, code, op)
 }
 
 // NewInternal returns an Error with a INTERNAL error code.
@@ -59,3 +81,63 @@ func NewMaximumAttempts(err error, message, op string) *Error {
 func NewExpired(err error, message, op string) *Error {
 	return newError(err, message, EXPIRED, op)
 }
+
+// NewCancelled returns an Error with a CANCELLED error code.
+func NewCancelled(err error, message, op string) *Error {
+	return newError(err, message, CANCELLED, op)
+}
+
+// NewDeadlineExceeded returns an Error with a DEADLINE_EXCEEDED error code.
+func NewDeadlineExceeded(err error, message, op string) *Error {
+	return newError(err, message, DEADLINE_EXCEEDED, op)
+}
+
+// NewUnauthenticated returns an Error with a UNAUTHENTICATED error code.
+func NewUnauthenticated(err error, message, op string) *Error {
+	return newError(err, message, UNAUTHENTICATED, op)
+}
+
+// NewPermissionDenied returns an Error with a PERMISSION_DENIED error code.
+func NewPermissionDenied(err error, message, op string) *Error {
+	return newError(err, message, PERMISSION_DENIED, op)
+}
+
+// NewUnavailable returns an Error with a UNAVAILABLE error code.
+func NewUnavailable(err error, message, op string) *Error {
+	return newError(err, message, UNAVAILABLE, op)
+}
+
+// NewUnimplemented returns an Error with a UNIMPLEMENTED error code.
+func NewUnimplemented(err error, message, op string) *Error {
+	return newError(err, message, UNIMPLEMENTED, op)
+}
+
+// NewFailedPrecondition returns an Error with a FAILED_PRECONDITION error code.
+func NewFailedPrecondition(err error, message, op string) *Error {
+	return newError(err, message, FAILED_PRECONDITION, op)
+}
+
+// NewAlreadyExists returns an Error with a ALREADY_EXISTS error code.
+func NewAlreadyExists(err error, message, op string) *Error {
+	return newError(err, message, ALREADY_EXISTS, op)
+}
+
+// NewAborted returns an Error with a ABORTED error code.
+func NewAborted(err error, message, op string) *Error {
+	return newError(err, message, ABORTED, op)
+}
+
+// NewOutOfRange returns an Error with a OUT_OF_RANGE error code.
+func NewOutOfRange(err error, message, op string) *Error {
+	return newError(err, message, OUT_OF_RANGE, op)
+}
+
+// NewDataLoss returns an Error with a DATA_LOSS error code.
+func NewDataLoss(err error, message, op string) *Error {
+	return newError(err, message, DATA_LOSS, op)
+}
+
+// NewResourceExhausted returns an Error with a RESOURCE_EXHAUSTED error code.
+func NewResourceExhausted(err error, message, op string) *Error {
+	return newError(err, message, RESOURCE_EXHAUSTED, op)
+}
@@ -6,9 +6,29 @@ package errors
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 )
 
+func newDepthHelper() *Error {
+	return NewDepth(1, nil, "message", CONFLICT, "op")
+}
+
+func TestNewDepth(t *testing.T) {
+	got := newDepthHelper()
+	if !reflect.DeepEqual(CONFLICT, got.Code) {
+		t.Fatalf("expecting %s, got %s", CONFLICT, got.Code)
+	}
+	if !strings.HasSuffix(got.FileLine(), "util_test.go:18") {
+		t.Fatalf("expecting util_test.go:18, got %s", got.FileLine())
+	}
+	frame, _ := got.RuntimeFrames().Next()
+	want := "github.com/ainsleyclark/errors.TestNewDepth"
+	if !reflect.DeepEqual(want, frame.Function) {
+		t.Fatalf("expecting %s, got %s", want, frame.Function)
+	}
+}
+
 func TestNewInternal(t *testing.T) {
 	got := NewInternal(nil, "message", "op")
 	want := INTERNAL
@@ -64,3 +84,99 @@ func TestNewExpired(t *testing.T) {
 		t.Fatalf("expecting %s, got %s", want, got)
 	}
 }
+
+func TestNewCancelled(t *testing.T) {
+	got := NewCancelled(nil, "message", "op")
+	want := CANCELLED
+	if !reflect.DeepEqual(want, got.Code) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
+
+func TestNewDeadlineExceeded(t *testing.T) {
+	got := NewDeadlineExceeded(nil, "message", "op")
+	want := DEADLINE_EXCEEDED
+	if !reflect.DeepEqual(want, got.Code) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
+
+func TestNewUnauthenticated(t *testing.T) {
+	got := NewUnauthenticated(nil, "message", "op")
+	want := UNAUTHENTICATED
+	if !reflect.DeepEqual(want, got.Code) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
+
+func TestNewPermissionDenied(t *testing.T) {
+	got := NewPermissionDenied(nil, "message", "op")
+	want := PERMISSION_DENIED
+	if !reflect.DeepEqual(want, got.Code) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
+
+func TestNewUnavailable(t *testing.T) {
+	got := NewUnavailable(nil, "message", "op")
+	want := UNAVAILABLE
+	if !reflect.DeepEqual(want, got.Code) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
+
+func TestNewUnimplemented(t *testing.T) {
+	got := NewUnimplemented(nil, "message", "op")
+	want := UNIMPLEMENTED
+	if !reflect.DeepEqual(want, got.Code) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
+
+func TestNewFailedPrecondition(t *testing.T) {
+	got := NewFailedPrecondition(nil, "message", "op")
+	want := FAILED_PRECONDITION
+	if !reflect.DeepEqual(want, got.Code) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
+
+func TestNewAlreadyExists(t *testing.T) {
+	got := NewAlreadyExists(nil, "message", "op")
+	want := ALREADY_EXISTS
+	if !reflect.DeepEqual(want, got.Code) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
+
+func TestNewAborted(t *testing.T) {
+	got := NewAborted(nil, "message", "op")
+	want := ABORTED
+	if !reflect.DeepEqual(want, got.Code) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
+
+func TestNewOutOfRange(t *testing.T) {
+	got := NewOutOfRange(nil, "message", "op")
+	want := OUT_OF_RANGE
+	if !reflect.DeepEqual(want, got.Code) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
+
+func TestNewDataLoss(t *testing.T) {
+	got := NewDataLoss(nil, "message", "op")
+	want := DATA_LOSS
+	if !reflect.DeepEqual(want, got.Code) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}
+
+func TestNewResourceExhausted(t *testing.T) {
+	got := NewResourceExhausted(nil, "message", "op")
+	want := RESOURCE_EXHAUSTED
+	if !reflect.DeepEqual(want, got.Code) {
+		t.Fatalf("expecting %s, got %s", want, got)
+	}
+}